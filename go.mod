@@ -3,6 +3,7 @@ module github.com/giantswarm/app/v5
 go 1.16
 
 require (
+	github.com/Masterminds/semver/v3 v3.1.1
 	github.com/giantswarm/apiextensions/v3 v3.32.0
 	github.com/giantswarm/k8smetadata v0.3.0
 	github.com/giantswarm/microerror v0.3.0
@@ -11,6 +12,7 @@ require (
 	github.com/google/go-cmp v0.5.6
 	github.com/google/go-github/v35 v35.3.0
 	github.com/imdario/mergo v0.3.12
+	github.com/prometheus/client_golang v1.5.1
 	golang.org/x/oauth2 v0.0.0-20210819190943-2bc19b11175f
 	k8s.io/api v0.18.19
 	k8s.io/apiextensions-apiserver v0.18.19
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 
 	applicationv1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
 	"github.com/giantswarm/k8smetadata/pkg/annotation"
@@ -14,22 +15,72 @@ import (
 )
 
 type Config struct {
-	AppCatalog          string
-	AppName             string
-	AppNamespace        string
-	AppVersion          string
+	// Annotations are added to the app CR. They take precedence over the
+	// annotations set by NewCR.
+	Annotations           map[string]string
+	AppCatalog            string
+	AppConfigMapName      string
+	AppConfigMapNamespace string
+	AppName               string
+	AppNamespace          string
+	AppSecretName         string
+	AppSecretNamespace    string
+	AppVersion            string
+	// CatalogNamespace is optional. By default app-operator looks up the
+	// catalog in the default and giantswarm namespaces.
+	CatalogNamespace    string
 	ConfigVersion       string
 	DisableForceUpgrade bool
-	Name                string
-	Namespace           string
-	UserConfigMapName   string
-	UserSecretName      string
+	// KubeConfigContext and KubeConfigSecretNamespace require
+	// KubeConfigSecretName. The app is installed in-cluster when
+	// KubeConfigSecretName is empty.
+	KubeConfigContext         string
+	KubeConfigSecretName      string
+	KubeConfigSecretNamespace string
+	// Labels are added to the app CR. They take precedence over the labels
+	// set by NewCR.
+	Labels               map[string]string
+	Name                 string
+	Namespace            string
+	NamespaceAnnotations map[string]string
+	NamespaceLabels      map[string]string
+	SkipCRDs             bool
+	UserConfigMapName    string
+	UserSecretName       string
 }
 
-// NewCR returns new application CR.
+// NewCR returns new application CR. An invalidConfigError is returned for
+// missing or contradicting settings.
 //
 // AppCatalog is the name of the app catalog where the app stored.
-func NewCR(c Config) *applicationv1alpha1.App {
+// Namespaces of configmaps and secrets default to the namespace of the app
+// CR.
+func NewCR(c Config) (*applicationv1alpha1.App, error) {
+	if c.Name == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Name must not be empty", c)
+	}
+	if c.AppCatalog == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.AppCatalog must not be empty", c)
+	}
+	if c.AppName == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.AppName must not be empty", c)
+	}
+	if c.AppVersion == "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.AppVersion must not be empty", c)
+	}
+	if c.AppConfigMapName == "" && c.AppConfigMapNamespace != "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.AppConfigMapNamespace must be empty when %T.AppConfigMapName is empty", c, c)
+	}
+	if c.AppSecretName == "" && c.AppSecretNamespace != "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.AppSecretNamespace must be empty when %T.AppSecretName is empty", c, c)
+	}
+	if c.KubeConfigSecretName == "" && c.KubeConfigSecretNamespace != "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.KubeConfigSecretNamespace must be empty when %T.KubeConfigSecretName is empty", c, c)
+	}
+	if c.KubeConfigSecretName == "" && c.KubeConfigContext != "" {
+		return nil, microerror.Maskf(invalidConfigError, "%T.KubeConfigContext must be empty when %T.KubeConfigSecretName is empty", c, c)
+	}
+
 	if c.Namespace == "" {
 		c.Namespace = "giantswarm"
 	}
@@ -42,6 +93,47 @@ func NewCR(c Config) *applicationv1alpha1.App {
 		if !c.DisableForceUpgrade {
 			annotations["chart-operator.giantswarm.io/force-helm-upgrade"] = "true"
 		}
+		for k, v := range c.Annotations {
+			annotations[k] = v
+		}
+	}
+
+	labels := map[string]string{
+		// Version 0.0.0 means this is reconciled by
+		// unique operator.
+		label.AppOperatorVersion: "0.0.0",
+	}
+	for k, v := range c.Labels {
+		labels[k] = v
+	}
+
+	var config applicationv1alpha1.AppSpecConfig
+	if c.AppConfigMapName != "" {
+		config.ConfigMap = applicationv1alpha1.AppSpecConfigConfigMap{
+			Name:      c.AppConfigMapName,
+			Namespace: defaultNamespace(c.AppConfigMapNamespace, c.Namespace),
+		}
+	}
+	if c.AppSecretName != "" {
+		config.Secret = applicationv1alpha1.AppSpecConfigSecret{
+			Name:      c.AppSecretName,
+			Namespace: defaultNamespace(c.AppSecretNamespace, c.Namespace),
+		}
+	}
+
+	kubeConfig := applicationv1alpha1.AppSpecKubeConfig{
+		InCluster: true,
+	}
+	if c.KubeConfigSecretName != "" {
+		kubeConfig = applicationv1alpha1.AppSpecKubeConfig{
+			Context: applicationv1alpha1.AppSpecKubeConfigContext{
+				Name: c.KubeConfigContext,
+			},
+			Secret: applicationv1alpha1.AppSpecKubeConfigSecret{
+				Name:      c.KubeConfigSecretName,
+				Namespace: defaultNamespace(c.KubeConfigSecretNamespace, c.Namespace),
+			},
+		}
 	}
 
 	var userConfig applicationv1alpha1.AppSpecUserConfig
@@ -64,42 +156,76 @@ func NewCR(c Config) *applicationv1alpha1.App {
 			Name:        c.Name,
 			Namespace:   c.Namespace,
 			Annotations: annotations,
-			Labels: map[string]string{
-				// Version 0.0.0 means this is reconciled by
-				// unique operator.
-				label.AppOperatorVersion: "0.0.0",
-			},
+			Labels:      labels,
 		},
 		Spec: applicationv1alpha1.AppSpec{
-			Catalog: c.AppCatalog,
-			KubeConfig: applicationv1alpha1.AppSpecKubeConfig{
-				InCluster: true,
+			Catalog:          c.AppCatalog,
+			CatalogNamespace: c.CatalogNamespace,
+			Config:           config,
+			Install: applicationv1alpha1.AppSpecInstall{
+				SkipCRDs: c.SkipCRDs,
 			},
+			KubeConfig: kubeConfig,
 			Name:       c.AppName,
 			Namespace:  c.AppNamespace,
+			NamespaceConfig: applicationv1alpha1.AppSpecNamespaceConfig{
+				Annotations: c.NamespaceAnnotations,
+				Labels:      c.NamespaceLabels,
+			},
 			Version:    c.AppVersion,
 			UserConfig: userConfig,
 		},
 	}
 
-	return appCR
+	return appCR, nil
 }
 
+// Marshal returns the app CR in the given format. Supported formats are
+// json, json-pretty, yaml, name, table, wide, jsonpath=<expression> and
+// go-template=<template>. An executionFailedError is returned for other
+// formats.
 func Marshal(appCR *applicationv1alpha1.App, format string) (string, error) {
 	var output []byte
 	var err error
 
-	switch format {
-	case "json":
+	switch {
+	case format == "json":
 		output, err = json.Marshal(appCR)
 		if err != nil {
 			return "", microerror.Mask(err)
 		}
-	case "yaml":
+	case format == "json-pretty":
+		output, err = json.MarshalIndent(appCR, "", "  ")
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+	case format == "yaml":
 		output, err = yaml.Marshal(appCR)
 		if err != nil {
 			return "", microerror.Mask(err)
 		}
+	case format == "name":
+		output = []byte(fmt.Sprintf("%s/%s\n", appResource, appCR.Name))
+	case format == "table":
+		output, err = marshalTable(appCR, false)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+	case format == "wide":
+		output, err = marshalTable(appCR, true)
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+	case strings.HasPrefix(format, jsonPathPrefix):
+		output, err = marshalJSONPath(appCR, strings.TrimPrefix(format, jsonPathPrefix))
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+	case strings.HasPrefix(format, goTemplatePrefix):
+		output, err = marshalGoTemplate(appCR, strings.TrimPrefix(format, goTemplatePrefix))
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
 	default:
 		return "", microerror.Maskf(executionFailedError, "format: %q", format)
 	}
@@ -120,3 +246,11 @@ func Print(w io.Writer, format string, appCR *applicationv1alpha1.App) error {
 
 	return nil
 }
+
+func defaultNamespace(namespace, defaultValue string) string {
+	if namespace == "" {
+		return defaultValue
+	}
+
+	return namespace
+}
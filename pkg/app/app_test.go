@@ -0,0 +1,193 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	applicationv1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/k8smetadata/pkg/label"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_NewCR(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      Config
+		expectedCR  *applicationv1alpha1.App
+		expectedErr string
+	}{
+		{
+			name: "case 0: in-cluster app with defaults",
+			config: Config{
+				AppCatalog:   "giantswarm",
+				AppName:      "kiam",
+				AppNamespace: "kube-system",
+				AppVersion:   "1.4.0",
+				Name:         "kiam",
+			},
+			expectedCR: &applicationv1alpha1.App{
+				TypeMeta: applicationv1alpha1.NewAppTypeMeta(),
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kiam",
+					Namespace: "giantswarm",
+					Annotations: map[string]string{
+						"chart-operator.giantswarm.io/force-helm-upgrade": "true",
+					},
+					Labels: map[string]string{
+						label.AppOperatorVersion: "0.0.0",
+					},
+				},
+				Spec: applicationv1alpha1.AppSpec{
+					Catalog: "giantswarm",
+					KubeConfig: applicationv1alpha1.AppSpecKubeConfig{
+						InCluster: true,
+					},
+					Name:      "kiam",
+					Namespace: "kube-system",
+					Version:   "1.4.0",
+				},
+			},
+		},
+		{
+			name: "case 1: app in workload cluster",
+			config: Config{
+				Annotations: map[string]string{
+					"chart-operator.giantswarm.io/force-helm-upgrade": "false",
+				},
+				AppCatalog:                "giantswarm",
+				AppConfigMapName:          "eggs2-cluster-values",
+				AppName:                   "kiam",
+				AppNamespace:              "kube-system",
+				AppSecretName:             "kiam-secrets",
+				AppSecretNamespace:        "giantswarm",
+				AppVersion:                "1.4.0",
+				CatalogNamespace:          "giantswarm",
+				KubeConfigContext:         "eggs2-admin@eggs2",
+				KubeConfigSecretName:      "eggs2-kubeconfig",
+				KubeConfigSecretNamespace: "eggs2",
+				Labels: map[string]string{
+					label.Cluster: "eggs2",
+				},
+				Name:      "eggs2-kiam",
+				Namespace: "eggs2",
+				NamespaceLabels: map[string]string{
+					"monitoring": "enabled",
+				},
+				SkipCRDs:       true,
+				UserSecretName: "kiam-user-secrets",
+			},
+			expectedCR: &applicationv1alpha1.App{
+				TypeMeta: applicationv1alpha1.NewAppTypeMeta(),
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "eggs2-kiam",
+					Namespace: "eggs2",
+					Annotations: map[string]string{
+						"chart-operator.giantswarm.io/force-helm-upgrade": "false",
+					},
+					Labels: map[string]string{
+						label.AppOperatorVersion: "0.0.0",
+						label.Cluster:            "eggs2",
+					},
+				},
+				Spec: applicationv1alpha1.AppSpec{
+					Catalog:          "giantswarm",
+					CatalogNamespace: "giantswarm",
+					Config: applicationv1alpha1.AppSpecConfig{
+						ConfigMap: applicationv1alpha1.AppSpecConfigConfigMap{
+							Name:      "eggs2-cluster-values",
+							Namespace: "eggs2",
+						},
+						Secret: applicationv1alpha1.AppSpecConfigSecret{
+							Name:      "kiam-secrets",
+							Namespace: "giantswarm",
+						},
+					},
+					Install: applicationv1alpha1.AppSpecInstall{
+						SkipCRDs: true,
+					},
+					KubeConfig: applicationv1alpha1.AppSpecKubeConfig{
+						Context: applicationv1alpha1.AppSpecKubeConfigContext{
+							Name: "eggs2-admin@eggs2",
+						},
+						Secret: applicationv1alpha1.AppSpecKubeConfigSecret{
+							Name:      "eggs2-kubeconfig",
+							Namespace: "eggs2",
+						},
+					},
+					Name:      "kiam",
+					Namespace: "kube-system",
+					NamespaceConfig: applicationv1alpha1.AppSpecNamespaceConfig{
+						Labels: map[string]string{
+							"monitoring": "enabled",
+						},
+					},
+					UserConfig: applicationv1alpha1.AppSpecUserConfig{
+						Secret: applicationv1alpha1.AppSpecUserConfigSecret{
+							Name:      "kiam-user-secrets",
+							Namespace: "eggs2",
+						},
+					},
+					Version: "1.4.0",
+				},
+			},
+		},
+		{
+			name: "case 2: missing version",
+			config: Config{
+				AppCatalog: "giantswarm",
+				AppName:    "kiam",
+				Name:       "kiam",
+			},
+			expectedErr: "app.Config.AppVersion must not be empty",
+		},
+		{
+			name: "case 3: kubeconfig context without secret",
+			config: Config{
+				AppCatalog:        "giantswarm",
+				AppName:           "kiam",
+				AppVersion:        "1.4.0",
+				KubeConfigContext: "eggs2-admin@eggs2",
+				Name:              "kiam",
+			},
+			expectedErr: "app.Config.KubeConfigContext must be empty when app.Config.KubeConfigSecretName is empty",
+		},
+		{
+			name: "case 4: app configmap namespace without name",
+			config: Config{
+				AppCatalog:            "giantswarm",
+				AppConfigMapNamespace: "eggs2",
+				AppName:               "kiam",
+				AppVersion:            "1.4.0",
+				Name:                  "kiam",
+			},
+			expectedErr: "app.Config.AppConfigMapNamespace must be empty when app.Config.AppConfigMapName is empty",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cr, err := NewCR(tc.config)
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("error == %#v, want invalid config error", err)
+				}
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+				return
+			}
+
+			if !reflect.DeepEqual(cr, tc.expectedCR) {
+				t.Fatalf("cr == %#v, want %#v", cr, tc.expectedCR)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	applicationv1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+const (
+	// userValuesKey is the single key of user configmaps and secrets as
+	// expected by the values package.
+	userValuesKey = "values"
+)
+
+// BundleConfig is the input of NewBundle.
+type BundleConfig struct {
+	Config
+
+	// UserValues and UserSecretValues are optional. NewBundle generates the
+	// user configmap and secret holding them. See LoadValuesFile.
+	UserValues       map[string]interface{}
+	UserSecretValues map[string]interface{}
+}
+
+// Bundle holds an app CR and the user configmap and secret generated for
+// it. UserConfigMap and UserSecret are nil when no values are given.
+type Bundle struct {
+	App           *applicationv1alpha1.App
+	UserConfigMap *corev1.ConfigMap
+	UserSecret    *corev1.Secret
+}
+
+// NewBundle returns the app CR for c plus a user configmap for
+// c.UserValues and a user secret for c.UserSecretValues. They are named
+// <name>-user-values and <name>-user-secrets unless c.UserConfigMapName or
+// c.UserSecretName are set. Apps in the default catalog must use these
+// names, an invalidConfigError is returned otherwise.
+func NewBundle(c BundleConfig) (*Bundle, error) {
+	if c.UserValues != nil && c.UserConfigMapName == "" {
+		c.UserConfigMapName = key.UserValuesConfigMapName(c.Name)
+	}
+	if c.UserSecretValues != nil && c.UserSecretName == "" {
+		c.UserSecretName = key.UserSecretsSecretName(c.Name)
+	}
+
+	appCR, err := NewCR(c.Config)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	// The names are checked like app-admission-controller does.
+	if name := key.RequiredUserConfigMapName(*appCR); c.UserConfigMapName != "" && name != "" && c.UserConfigMapName != name {
+		return nil, microerror.Maskf(invalidConfigError, "%T.UserConfigMapName must be %#q for app in default catalog", c.Config, name)
+	}
+	if name := key.RequiredUserSecretName(*appCR); c.UserSecretName != "" && name != "" && c.UserSecretName != name {
+		return nil, microerror.Maskf(invalidConfigError, "%T.UserSecretName must be %#q for app in default catalog", c.Config, name)
+	}
+
+	b := &Bundle{
+		App: appCR,
+	}
+
+	if c.UserValues != nil {
+		data, err := yaml.Marshal(c.UserValues)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		b.UserConfigMap = &corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "ConfigMap",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      appCR.Spec.UserConfig.ConfigMap.Name,
+				Namespace: appCR.Spec.UserConfig.ConfigMap.Namespace,
+			},
+			Data: map[string]string{
+				userValuesKey: string(data),
+			},
+		}
+	}
+
+	if c.UserSecretValues != nil {
+		data, err := yaml.Marshal(c.UserSecretValues)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		b.UserSecret = &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: "v1",
+				Kind:       "Secret",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      appCR.Spec.UserConfig.Secret.Name,
+				Namespace: appCR.Spec.UserConfig.Secret.Namespace,
+			},
+			Data: map[string][]byte{
+				userValuesKey: data,
+			},
+		}
+	}
+
+	return b, nil
+}
+
+// LoadValuesFile reads values from a YAML or JSON file for
+// BundleConfig.UserValues and BundleConfig.UserSecretValues.
+func LoadValuesFile(path string) (map[string]interface{}, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	values := map[string]interface{}{}
+	err = yaml.Unmarshal(b, &values)
+	if err != nil {
+		return nil, microerror.Maskf(executionFailedError, "values file %#q: %s", path, err)
+	}
+
+	return values, nil
+}
+
+// MarshalBundle returns the user configmap, user secret and app CR as one
+// multi-document stream in json or yaml format. The configmap and secret
+// come first so they exist when the app CR is applied.
+func MarshalBundle(b *Bundle, format string) (string, error) {
+	var objects []interface{}
+	if b.UserConfigMap != nil {
+		objects = append(objects, b.UserConfigMap)
+	}
+	if b.UserSecret != nil {
+		objects = append(objects, b.UserSecret)
+	}
+	objects = append(objects, b.App)
+
+	var output []byte
+	for _, obj := range objects {
+		var doc []byte
+		var err error
+
+		switch format {
+		case "json":
+			doc, err = json.Marshal(obj)
+			if err != nil {
+				return "", microerror.Mask(err)
+			}
+			doc = append(doc, '\n')
+		case "yaml":
+			doc, err = yaml.Marshal(obj)
+			if err != nil {
+				return "", microerror.Mask(err)
+			}
+		default:
+			return "", microerror.Maskf(executionFailedError, "format: %q", format)
+		}
+
+		output = append(output, "---\n"...)
+		output = append(output, doc...)
+	}
+
+	return string(output), nil
+}
+
+// PrintBundle writes the bundle in the format of MarshalBundle.
+func PrintBundle(w io.Writer, format string, b *Bundle) error {
+	output, err := MarshalBundle(b, format)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	_, err = fmt.Fprintf(w, "%s", output)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
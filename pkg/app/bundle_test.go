@@ -0,0 +1,207 @@
+package app
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/app/v5/pkg/values"
+)
+
+func Test_NewBundle(t *testing.T) {
+	tests := []struct {
+		name                string
+		config              BundleConfig
+		format              string
+		expectedConfigMap   string
+		expectedSecret      string
+		expectedUserValues  map[string]interface{}
+		expectedUserSecrets map[string]interface{}
+		expectedErr         string
+	}{
+		{
+			name: "case 0: user values and secrets",
+			config: BundleConfig{
+				Config: Config{
+					AppCatalog: "default",
+					AppName:    "kiam",
+					AppVersion: "1.4.0",
+					Name:       "kiam",
+					Namespace:  "eggs2",
+				},
+				UserValues: map[string]interface{}{
+					"replicas": 2,
+				},
+				UserSecretValues: map[string]interface{}{
+					"aws": map[string]interface{}{
+						"accessKey": "secret",
+					},
+				},
+			},
+			format:            "yaml",
+			expectedConfigMap: "kiam-user-values",
+			expectedSecret:    "kiam-user-secrets",
+			expectedUserValues: map[string]interface{}{
+				"replicas": float64(2),
+			},
+			expectedUserSecrets: map[string]interface{}{
+				"aws": map[string]interface{}{
+					"accessKey": "secret",
+				},
+			},
+		},
+		{
+			name: "case 1: user values only as json",
+			config: BundleConfig{
+				Config: Config{
+					AppCatalog:        "giantswarm",
+					AppName:           "kiam",
+					AppVersion:        "1.4.0",
+					Name:              "kiam",
+					UserConfigMapName: "kiam-values",
+				},
+				UserValues: map[string]interface{}{
+					"replicas": 2,
+				},
+			},
+			format:            "json",
+			expectedConfigMap: "kiam-values",
+			expectedUserValues: map[string]interface{}{
+				"replicas": float64(2),
+			},
+		},
+		{
+			name: "case 2: wrong user secret name in default catalog",
+			config: BundleConfig{
+				Config: Config{
+					AppCatalog:     "default",
+					AppName:        "kiam",
+					AppVersion:     "1.4.0",
+					Name:           "kiam",
+					UserSecretName: "kiam-secrets",
+				},
+				UserSecretValues: map[string]interface{}{
+					"token": "secret",
+				},
+			},
+			expectedErr: "app.Config.UserSecretName must be `kiam-user-secrets` for app in default catalog",
+		},
+		{
+			name: "case 3: nginx ingress controller may name its user configmap",
+			config: BundleConfig{
+				Config: Config{
+					AppCatalog:        "default",
+					AppName:           "nginx-ingress-controller-app",
+					AppVersion:        "2.0.0",
+					Name:              "nginx-ingress-controller",
+					UserConfigMapName: "ingress-values",
+				},
+				UserValues: map[string]interface{}{
+					"replicas": 2,
+				},
+			},
+			format:            "yaml",
+			expectedConfigMap: "ingress-values",
+			expectedUserValues: map[string]interface{}{
+				"replicas": float64(2),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := NewBundle(tc.config)
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("error == %#v, want invalid config error", err)
+				}
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+				return
+			}
+
+			output, err := MarshalBundle(b, tc.format)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			// The bundle must be readable by the decoder and the values
+			// by the values package.
+			objects, err := DecodeObjects(strings.NewReader(output))
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+			if len(objects.Apps) != 1 {
+				t.Fatalf("len(apps) == %d, want 1", len(objects.Apps))
+			}
+
+			app := objects.Apps[0]
+			if app.Spec.UserConfig.ConfigMap.Name != tc.expectedConfigMap {
+				t.Fatalf("user configmap == %#q, want %#q", app.Spec.UserConfig.ConfigMap.Name, tc.expectedConfigMap)
+			}
+			if app.Spec.UserConfig.Secret.Name != tc.expectedSecret {
+				t.Fatalf("user secret == %#q, want %#q", app.Spec.UserConfig.Secret.Name, tc.expectedSecret)
+			}
+
+			var userValues, userSecrets map[string]interface{}
+			for _, configMap := range objects.ConfigMaps {
+				if configMap.Name != tc.expectedConfigMap || configMap.Namespace != app.Namespace {
+					t.Fatalf("configmap == %s/%s, want %s/%s", configMap.Namespace, configMap.Name, app.Namespace, tc.expectedConfigMap)
+				}
+				userValues, err = values.ExtractConfigMapData("user", configMap.Data)
+				if err != nil {
+					t.Fatalf("error == %#v, want nil", err)
+				}
+			}
+			for _, secret := range objects.Secrets {
+				if secret.Name != tc.expectedSecret || secret.Namespace != app.Namespace {
+					t.Fatalf("secret == %s/%s, want %s/%s", secret.Namespace, secret.Name, app.Namespace, tc.expectedSecret)
+				}
+				userSecrets, err = values.ExtractSecretData("user", secret.Data)
+				if err != nil {
+					t.Fatalf("error == %#v, want nil", err)
+				}
+			}
+
+			if !reflect.DeepEqual(userValues, tc.expectedUserValues) {
+				t.Fatalf("user values == %#v, want %#v", userValues, tc.expectedUserValues)
+			}
+			if !reflect.DeepEqual(userSecrets, tc.expectedUserSecrets) {
+				t.Fatalf("user secrets == %#v, want %#v", userSecrets, tc.expectedUserSecrets)
+			}
+		})
+	}
+}
+
+func Test_LoadValuesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.yaml")
+	err := ioutil.WriteFile(path, []byte("replicas: 2\nimage:\n  tag: 1.4.0\n"), 0600)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	v, err := LoadValuesFile(path)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	expected := map[string]interface{}{
+		"replicas": float64(2),
+		"image": map[string]interface{}{
+			"tag": "1.4.0",
+		},
+	}
+	if !reflect.DeepEqual(v, expected) {
+		t.Fatalf("values == %#v, want %#v", v, expected)
+	}
+}
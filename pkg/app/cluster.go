@@ -0,0 +1,73 @@
+package app
+
+import (
+	applicationv1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/k8smetadata/pkg/label"
+	"github.com/giantswarm/microerror"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+// NewClusterCR returns new application CR installing the app in the
+// workload cluster with the given ID. The app CR is created in the cluster
+// namespace unless c.Namespace is set. It uses the kubeconfig secret and
+// cluster values configmap generated for the cluster and is labelled with
+// the cluster ID and organization. An invalidConfigError is returned when c
+// references another kubeconfig secret or app configmap.
+func NewClusterCR(clusterID, organization string, c Config) (*applicationv1alpha1.App, error) {
+	if clusterID == "" {
+		return nil, microerror.Maskf(invalidConfigError, "cluster ID must not be empty")
+	}
+
+	// The key functions derive the names from the cluster namespace which
+	// is named after the cluster ID.
+	clusterCR := applicationv1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: clusterID,
+		},
+		Spec: applicationv1alpha1.AppSpec{
+			Name: c.AppName,
+		},
+	}
+
+	kubeConfigSecretName := key.ClusterKubeConfigSecretName(clusterCR)
+	if !matches(c.KubeConfigSecretName, kubeConfigSecretName) || !matches(c.KubeConfigSecretNamespace, clusterID) {
+		return nil, microerror.Maskf(invalidConfigError, "%T.KubeConfigSecretName must be empty or %#q in namespace %#q for cluster %#q", c, kubeConfigSecretName, clusterID, clusterID)
+	}
+	c.KubeConfigSecretName = kubeConfigSecretName
+	c.KubeConfigSecretNamespace = clusterID
+
+	clusterConfigMapName := key.ClusterConfigMapName(clusterCR)
+	if !matches(c.AppConfigMapName, clusterConfigMapName) || !matches(c.AppConfigMapNamespace, clusterID) {
+		return nil, microerror.Maskf(invalidConfigError, "%T.AppConfigMapName must be empty or %#q in namespace %#q for cluster %#q", c, clusterConfigMapName, clusterID, clusterID)
+	}
+	c.AppConfigMapName = clusterConfigMapName
+	c.AppConfigMapNamespace = clusterID
+
+	if c.Namespace == "" {
+		c.Namespace = clusterID
+	}
+
+	labels := map[string]string{
+		label.Cluster: clusterID,
+	}
+	if organization != "" {
+		labels[label.Organization] = organization
+	}
+	for k, v := range c.Labels {
+		labels[k] = v
+	}
+	c.Labels = labels
+
+	appCR, err := NewCR(c)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return appCR, nil
+}
+
+func matches(value, expected string) bool {
+	return value == "" || value == expected
+}
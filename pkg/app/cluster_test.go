@@ -0,0 +1,141 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	applicationv1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/k8smetadata/pkg/label"
+)
+
+func Test_NewClusterCR(t *testing.T) {
+	tests := []struct {
+		name               string
+		clusterID          string
+		organization       string
+		config             Config
+		expectedNamespace  string
+		expectedConfig     applicationv1alpha1.AppSpecConfig
+		expectedKubeConfig applicationv1alpha1.AppSpecKubeConfig
+		expectedLabels     map[string]string
+		expectedErr        string
+	}{
+		{
+			name:         "case 0: app in cluster namespace",
+			clusterID:    "eggs2",
+			organization: "acme",
+			config: Config{
+				AppCatalog:   "giantswarm",
+				AppName:      "kiam",
+				AppNamespace: "kube-system",
+				AppVersion:   "1.4.0",
+				Name:         "kiam",
+			},
+			expectedNamespace: "eggs2",
+			expectedConfig: applicationv1alpha1.AppSpecConfig{
+				ConfigMap: applicationv1alpha1.AppSpecConfigConfigMap{
+					Name:      "eggs2-cluster-values",
+					Namespace: "eggs2",
+				},
+			},
+			expectedKubeConfig: applicationv1alpha1.AppSpecKubeConfig{
+				Secret: applicationv1alpha1.AppSpecKubeConfigSecret{
+					Name:      "eggs2-kubeconfig",
+					Namespace: "eggs2",
+				},
+			},
+			expectedLabels: map[string]string{
+				label.AppOperatorVersion: "0.0.0",
+				label.Cluster:            "eggs2",
+				label.Organization:       "acme",
+			},
+		},
+		{
+			name:      "case 1: nginx ingress controller in organization namespace",
+			clusterID: "eggs2",
+			config: Config{
+				AppCatalog:   "giantswarm",
+				AppName:      "nginx-ingress-controller-app",
+				AppNamespace: "kube-system",
+				AppVersion:   "1.9.0",
+				Name:         "eggs2-nginx-ingress-controller-app",
+				Namespace:    "org-acme",
+			},
+			expectedNamespace: "org-acme",
+			expectedConfig: applicationv1alpha1.AppSpecConfig{
+				ConfigMap: applicationv1alpha1.AppSpecConfigConfigMap{
+					Name:      "ingress-controller-values",
+					Namespace: "eggs2",
+				},
+			},
+			expectedKubeConfig: applicationv1alpha1.AppSpecKubeConfig{
+				Secret: applicationv1alpha1.AppSpecKubeConfigSecret{
+					Name:      "eggs2-kubeconfig",
+					Namespace: "eggs2",
+				},
+			},
+			expectedLabels: map[string]string{
+				label.AppOperatorVersion: "0.0.0",
+				label.Cluster:            "eggs2",
+			},
+		},
+		{
+			name:      "case 2: other kubeconfig secret",
+			clusterID: "eggs2",
+			config: Config{
+				AppCatalog:           "giantswarm",
+				AppName:              "kiam",
+				AppVersion:           "1.4.0",
+				KubeConfigSecretName: "kubeconfig",
+				Name:                 "kiam",
+			},
+			expectedErr: "app.Config.KubeConfigSecretName must be empty or `eggs2-kubeconfig` in namespace `eggs2` for cluster `eggs2`",
+		},
+		{
+			name: "case 3: missing cluster ID",
+			config: Config{
+				AppCatalog: "giantswarm",
+				AppName:    "kiam",
+				AppVersion: "1.4.0",
+				Name:       "kiam",
+			},
+			expectedErr: "cluster ID must not be empty",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cr, err := NewClusterCR(tc.clusterID, tc.organization, tc.config)
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil {
+				if !IsInvalidConfig(err) {
+					t.Fatalf("error == %#v, want invalid config error", err)
+				}
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+				return
+			}
+
+			if cr.Namespace != tc.expectedNamespace {
+				t.Fatalf("namespace == %#q, want %#q", cr.Namespace, tc.expectedNamespace)
+			}
+			if !reflect.DeepEqual(cr.Spec.Config, tc.expectedConfig) {
+				t.Fatalf("config == %#v, want %#v", cr.Spec.Config, tc.expectedConfig)
+			}
+			if !reflect.DeepEqual(cr.Spec.KubeConfig, tc.expectedKubeConfig) {
+				t.Fatalf("kubeconfig == %#v, want %#v", cr.Spec.KubeConfig, tc.expectedKubeConfig)
+			}
+			if !reflect.DeepEqual(cr.Labels, tc.expectedLabels) {
+				t.Fatalf("labels == %#v, want %#v", cr.Labels, tc.expectedLabels)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+
+	applicationv1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// Objects holds app CRs and their companion configmaps and secrets decoded
+// from a stream.
+type Objects struct {
+	Apps       []applicationv1alpha1.App
+	ConfigMaps []corev1.ConfigMap
+	Secrets    []corev1.Secret
+}
+
+// Decode reads app CRs from a stream of YAML or JSON documents separated by
+// --- or of concatenated JSON objects. Documents are indexed in the order
+// they are read. Empty documents are skipped and not counted. A
+// decodingFailedError naming the index of the document is returned for
+// documents of other kinds, for unknown or duplicate fields and for
+// trailing content.
+func Decode(r io.Reader) ([]applicationv1alpha1.App, error) {
+	objects, err := decode(r, false)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return objects.Apps, nil
+}
+
+// DecodeObjects is like Decode but also accepts configmaps and secrets, e.g.
+// the user values of the app CRs.
+func DecodeObjects(r io.Reader) (*Objects, error) {
+	objects, err := decode(r, true)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return objects, nil
+}
+
+func decode(r io.Reader, withCompanions bool) (*Objects, error) {
+	objects := &Objects{}
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+
+	var i int
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, microerror.Maskf(decodingFailedError, "document %d: %s", i, err)
+		}
+
+		doc = bytes.TrimSpace(doc)
+		if len(doc) == 0 {
+			continue
+		}
+
+		// A YAML document may hold a stream of concatenated JSON objects.
+		// Documents which do not start with a JSON object, e.g. YAML flow
+		// mappings, are decoded as one object.
+		decoder := json.NewDecoder(bytes.NewReader(doc))
+		var raw json.RawMessage
+		if doc[0] != '{' || decoder.Decode(&raw) != nil {
+			err = objects.add(i, doc, withCompanions)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			i++
+			continue
+		}
+
+		for {
+			err = objects.add(i, raw, withCompanions)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+			i++
+
+			raw = nil
+			err = decoder.Decode(&raw)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, microerror.Maskf(decodingFailedError, "document %d: %s", i, err)
+			}
+		}
+	}
+
+	return objects, nil
+}
+
+func (o *Objects) add(i int, doc []byte, withCompanions bool) error {
+	var typeMeta metav1.TypeMeta
+	err := yaml.Unmarshal(doc, &typeMeta)
+	if err != nil {
+		return microerror.Maskf(decodingFailedError, "document %d: %s", i, err)
+	}
+
+	appTypeMeta := applicationv1alpha1.NewAppTypeMeta()
+
+	switch {
+	case typeMeta == appTypeMeta:
+		var app applicationv1alpha1.App
+		err = yaml.UnmarshalStrict(doc, &app)
+		if err != nil {
+			return microerror.Maskf(decodingFailedError, "document %d: %s", i, err)
+		}
+		o.Apps = append(o.Apps, app)
+	case withCompanions && typeMeta.APIVersion == "v1" && typeMeta.Kind == "ConfigMap":
+		var configMap corev1.ConfigMap
+		err = yaml.UnmarshalStrict(doc, &configMap)
+		if err != nil {
+			return microerror.Maskf(decodingFailedError, "document %d: %s", i, err)
+		}
+		o.ConfigMaps = append(o.ConfigMaps, configMap)
+	case withCompanions && typeMeta.APIVersion == "v1" && typeMeta.Kind == "Secret":
+		var secret corev1.Secret
+		err = yaml.UnmarshalStrict(doc, &secret)
+		if err != nil {
+			return microerror.Maskf(decodingFailedError, "document %d: %s", i, err)
+		}
+		o.Secrets = append(o.Secrets, secret)
+	default:
+		return microerror.Maskf(decodingFailedError, "document %d: kind %q of api version %q is not supported", i, typeMeta.Kind, typeMeta.APIVersion)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	testAppYAML = `apiVersion: application.giantswarm.io/v1alpha1
+kind: App
+metadata:
+  name: kiam
+  namespace: eggs2
+spec:
+  catalog: giantswarm
+  name: kiam
+  namespace: kube-system
+  version: 1.4.0
+  kubeConfig:
+    inCluster: true
+  userConfig:
+    configMap:
+      name: kiam-user-values
+      namespace: eggs2
+`
+	testAppJSON = `{"apiVersion": "application.giantswarm.io/v1alpha1", "kind": "App", "metadata": {"name": "cert-manager", "namespace": "eggs2"}, "spec": {"catalog": "giantswarm", "name": "cert-manager", "namespace": "kube-system", "version": "2.0.0", "kubeConfig": {"inCluster": true}}}
+`
+	testConfigMapYAML = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kiam-user-values
+  namespace: eggs2
+data:
+  values: |
+    replicas: 2
+`
+)
+
+func Test_Decode(t *testing.T) {
+	tests := []struct {
+		name               string
+		input              string
+		withCompanions     bool
+		expectedApps       []string
+		expectedConfigMaps int
+		expectedErr        string
+	}{
+		{
+			name:         "case 0: single YAML document",
+			input:        testAppYAML,
+			expectedApps: []string{"kiam"},
+		},
+		{
+			name:         "case 1: YAML and JSON documents",
+			input:        "---\n" + testAppYAML + "---\n" + testAppJSON + "---\n",
+			expectedApps: []string{"kiam", "cert-manager"},
+		},
+		{
+			name:        "case 2: configmap without companions",
+			input:       testAppYAML + "---\n" + testConfigMapYAML,
+			expectedErr: "document 1: kind \"ConfigMap\" of api version \"v1\" is not supported",
+		},
+		{
+			name:               "case 3: configmap with companions",
+			input:              testAppYAML + "---\n" + testConfigMapYAML,
+			withCompanions:     true,
+			expectedApps:       []string{"kiam"},
+			expectedConfigMaps: 1,
+		},
+		{
+			name:        "case 4: unknown field",
+			input:       testAppJSON + "---\n" + strings.Replace(testAppYAML, "  version: 1.4.0", "  version: 1.4.0\n  versoin: 1.5.0", 1),
+			expectedErr: "document 1: error unmarshaling JSON: while decoding JSON: json: unknown field \"versoin\"",
+		},
+		{
+			name:        "case 5: wrong api version",
+			input:       strings.Replace(testAppYAML, "v1alpha1", "v1alpha2", 1),
+			expectedErr: "document 0: kind \"App\" of api version \"application.giantswarm.io/v1alpha2\" is not supported",
+		},
+		{
+			name:        "case 6: broken YAML",
+			input:       testAppYAML + "---\nkind: [",
+			expectedErr: "document 1:",
+		},
+		{
+			name:         "case 7: concatenated JSON objects",
+			input:        testAppJSON + strings.Replace(testAppJSON, "cert-manager", "external-dns", 2),
+			expectedApps: []string{"cert-manager", "external-dns"},
+		},
+		{
+			name:        "case 8: trailing content after JSON object",
+			input:       testAppJSON + "]",
+			expectedErr: "document 1:",
+		},
+		{
+			name:        "case 9: unknown field in second JSON object",
+			input:       testAppJSON + strings.Replace(testAppJSON, `"version"`, `"versoin"`, 1),
+			expectedErr: "document 1: error unmarshaling JSON: while decoding JSON: json: unknown field \"versoin\"",
+		},
+		{
+			name:         "case 10: YAML flow mapping",
+			input:        testAppJSON + "---\n{apiVersion: application.giantswarm.io/v1alpha1, kind: App, metadata: {name: kiam, namespace: eggs2}}\n",
+			expectedApps: []string{"cert-manager", "kiam"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var objects *Objects
+			var err error
+			if tc.withCompanions {
+				objects, err = DecodeObjects(strings.NewReader(tc.input))
+			} else {
+				objects = &Objects{}
+				objects.Apps, err = Decode(strings.NewReader(tc.input))
+			}
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil {
+				if !IsDecodingFailed(err) {
+					t.Fatalf("error == %#v, want decoding failed error", err)
+				}
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+				return
+			}
+
+			var apps []string
+			for _, app := range objects.Apps {
+				apps = append(apps, app.Name)
+			}
+			if strings.Join(apps, ",") != strings.Join(tc.expectedApps, ",") {
+				t.Fatalf("apps == %#v, want %#v", apps, tc.expectedApps)
+			}
+			if len(objects.ConfigMaps) != tc.expectedConfigMaps {
+				t.Fatalf("len(configmaps) == %d, want %d", len(objects.ConfigMaps), tc.expectedConfigMaps)
+			}
+		})
+	}
+}
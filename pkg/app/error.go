@@ -11,3 +11,21 @@ var executionFailedError = &microerror.Error{
 func IsExecutionFailedError(err error) bool {
 	return microerror.Cause(err) == executionFailedError
 }
+
+var invalidConfigError = &microerror.Error{
+	Kind: "invalidConfigError",
+}
+
+// IsInvalidConfig asserts invalidConfigError.
+func IsInvalidConfig(err error) bool {
+	return microerror.Cause(err) == invalidConfigError
+}
+
+var decodingFailedError = &microerror.Error{
+	Kind: "decodingFailedError",
+}
+
+// IsDecodingFailed asserts decodingFailedError.
+func IsDecodingFailed(err error) bool {
+	return microerror.Cause(err) == decodingFailedError
+}
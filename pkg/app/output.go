@@ -0,0 +1,127 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	applicationv1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	// appResource is the resource name printed by the name format, the
+	// same as kubectl prints.
+	appResource = "app.application.giantswarm.io"
+
+	goTemplatePrefix = "go-template="
+	jsonPathPrefix   = "jsonpath="
+)
+
+func marshalTable(appCR *applicationv1alpha1.App, wide bool) ([]byte, error) {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 8, 3, ' ', 0)
+
+	columns := []string{"NAME", "NAMESPACE", "CATALOG", "APP", "VERSION", "STATUS"}
+	values := []string{
+		appCR.Name,
+		appCR.Namespace,
+		appCR.Spec.Catalog,
+		appCR.Spec.Name,
+		appCR.Spec.Version,
+		appCR.Status.Release.Status,
+	}
+	if wide {
+		columns = append(columns, "TARGET NAMESPACE", "INSTALLED VERSION", "IN CLUSTER")
+		values = append(values,
+			appCR.Spec.Namespace,
+			appCR.Status.Version,
+			fmt.Sprintf("%t", appCR.Spec.KubeConfig.InCluster),
+		)
+	}
+
+	for i := range values {
+		if values[i] == "" {
+			values[i] = "<none>"
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%s\n%s\n", strings.Join(columns, "\t"), strings.Join(values, "\t"))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func marshalJSONPath(appCR *applicationv1alpha1.App, expression string) ([]byte, error) {
+	// Like kubectl the braces around the expression are optional.
+	if !strings.HasPrefix(expression, "{") {
+		expression = fmt.Sprintf("{%s}", expression)
+	}
+
+	p := jsonpath.New("app")
+	err := p.Parse(expression)
+	if err != nil {
+		return nil, microerror.Maskf(executionFailedError, "jsonpath %q: %s", expression, err)
+	}
+
+	data, err := toJSONData(appCR)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var buf bytes.Buffer
+	err = p.Execute(&buf, data)
+	if err != nil {
+		return nil, microerror.Maskf(executionFailedError, "jsonpath %q: %s", expression, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func marshalGoTemplate(appCR *applicationv1alpha1.App, text string) ([]byte, error) {
+	t, err := template.New("app").Parse(text)
+	if err != nil {
+		return nil, microerror.Maskf(executionFailedError, "go-template %q: %s", text, err)
+	}
+
+	data, err := toJSONData(appCR)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var buf bytes.Buffer
+	err = t.Execute(&buf, data)
+	if err != nil {
+		return nil, microerror.Maskf(executionFailedError, "go-template %q: %s", text, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// toJSONData converts the app CR to generic JSON data so jsonpath
+// expressions and templates use the JSON field names, e.g. .spec.version.
+func toJSONData(appCR *applicationv1alpha1.App) (interface{}, error) {
+	b, err := json.Marshal(appCR)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	var data interface{}
+	err = json.Unmarshal(b, &data)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,131 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	applicationv1alpha1 "github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_Marshal(t *testing.T) {
+	appCR := &applicationv1alpha1.App{
+		TypeMeta: applicationv1alpha1.NewAppTypeMeta(),
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kiam",
+			Namespace: "giantswarm",
+		},
+		Spec: applicationv1alpha1.AppSpec{
+			Catalog:   "giantswarm",
+			Name:      "kiam",
+			Namespace: "kube-system",
+			Version:   "1.4.0",
+			KubeConfig: applicationv1alpha1.AppSpecKubeConfig{
+				InCluster: true,
+			},
+		},
+		Status: applicationv1alpha1.AppStatus{
+			Release: applicationv1alpha1.AppStatusRelease{
+				Status: "deployed",
+			},
+			Version: "1.3.0",
+		},
+	}
+
+	tests := []struct {
+		name           string
+		format         string
+		expectedOutput string
+		expectedErr    string
+	}{
+		{
+			name:           "case 0: name",
+			format:         "name",
+			expectedOutput: "app.application.giantswarm.io/kiam\n",
+		},
+		{
+			name:   "case 1: table",
+			format: "table",
+			expectedOutput: strings.Join([]string{
+				"NAME   NAMESPACE    CATALOG      APP    VERSION   STATUS",
+				"kiam   giantswarm   giantswarm   kiam   1.4.0     deployed",
+				"",
+			}, "\n"),
+		},
+		{
+			name:   "case 2: wide",
+			format: "wide",
+			expectedOutput: strings.Join([]string{
+				"NAME   NAMESPACE    CATALOG      APP    VERSION   STATUS     TARGET NAMESPACE   INSTALLED VERSION   IN CLUSTER",
+				"kiam   giantswarm   giantswarm   kiam   1.4.0     deployed   kube-system        1.3.0               true",
+				"",
+			}, "\n"),
+		},
+		{
+			name:           "case 3: jsonpath",
+			format:         "jsonpath={.spec.catalog}/{.spec.name}@{.spec.version}",
+			expectedOutput: "giantswarm/kiam@1.4.0",
+		},
+		{
+			name:           "case 4: jsonpath without braces",
+			format:         "jsonpath=.status.release.status",
+			expectedOutput: "deployed",
+		},
+		{
+			name:           "case 5: go template",
+			format:         "go-template={{.metadata.name}} {{.spec.version}}",
+			expectedOutput: "kiam 1.4.0",
+		},
+		{
+			name:           "case 6: compact json",
+			format:         "json",
+			expectedOutput: `{"kind":"App","apiVersion":"application.giantswarm.io/v1alpha1","metadata":{"name":"kiam"`,
+		},
+		{
+			name:           "case 7: pretty json",
+			format:         "json-pretty",
+			expectedOutput: "{\n  \"kind\": \"App\",\n  \"apiVersion\": \"application.giantswarm.io/v1alpha1\",\n",
+		},
+		{
+			name:        "case 8: invalid jsonpath",
+			format:      "jsonpath={.spec.version",
+			expectedErr: "jsonpath \"{.spec.version\"",
+		},
+		{
+			name:        "case 9: invalid go template",
+			format:      "go-template={{.spec.version",
+			expectedErr: "go-template \"{{.spec.version\"",
+		},
+		{
+			name:        "case 10: unknown format",
+			format:      "xml",
+			expectedErr: "format: \"xml\"",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := Marshal(appCR, tc.format)
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil {
+				if !IsExecutionFailedError(err) {
+					t.Fatalf("error == %#v, want execution failed error", err)
+				}
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+				return
+			}
+
+			if !strings.HasPrefix(output, tc.expectedOutput) {
+				t.Fatalf("output == %q, want %q", output, tc.expectedOutput)
+			}
+		})
+	}
+}
@@ -11,10 +11,27 @@ import (
 )
 
 const (
-	ChartOperatorAppName = "chart-operator"
+	// AppDependsOnAnnotation lists the app CRs in the same namespace that
+	// must be installed before this app. Entries are comma separated and
+	// may specify a minimum version.
+	// e.g. cert-manager-app>=2.4.0,kyverno
+	AppDependsOnAnnotation = "app-operator.giantswarm.io/depends-on"
+	ChartOperatorAppName   = "chart-operator"
+	// CordonUntilDateLayout is the time layout of the cordon until
+	// annotations. Dates are in UTC.
+	CordonUntilDateLayout = "2006-01-02T15:04:05"
+	// DefaultCatalogName is the catalog whose apps must name their user
+	// configmap and secret after the app CR.
+	DefaultCatalogName = "default"
+	// KubeConfigSecretKey is the data key of the kubeconfig secret referenced
+	// by app CRs installed in remote clusters.
+	KubeConfigSecretKey = "kubeConfig"
 	// LegacyAppVersionLabel was used for app CRs deployed with Helm 2.
 	// We now always default the value for this label.
 	LegacyAppVersionLabel = "1.0.0"
+	// NginxIngressControllerAppName is exempt from the user configmap naming
+	// convention of the default catalog.
+	NginxIngressControllerAppName = "nginx-ingress-controller-app"
 )
 
 func AppConfigMapName(customResource v1alpha1.App) string {
@@ -33,6 +50,10 @@ func AppNamespace(customResource v1alpha1.App) string {
 	return customResource.Spec.Namespace
 }
 
+func AppDependsOn(customResource v1alpha1.App) string {
+	return customResource.GetAnnotations()[AppDependsOnAnnotation]
+}
+
 func AppKubernetesNameLabel(customResource v1alpha1.App) string {
 	if val, ok := customResource.ObjectMeta.Labels[label.AppKubernetesName]; ok {
 		return val
@@ -98,7 +119,7 @@ func CordonUntil(customResource v1alpha1.App) string {
 }
 
 func CordonUntilDate() string {
-	return time.Now().Add(1 * time.Hour).Format("2006-01-02T15:04:05")
+	return time.Now().UTC().Add(1 * time.Hour).Format(CordonUntilDateLayout)
 }
 
 func DefaultCatalogStorageURL() string {
@@ -169,6 +190,29 @@ func ToApp(v interface{}) (v1alpha1.App, error) {
 	return *customResource, nil
 }
 
+// RequiredUserConfigMapName returns the name the user configmap of the app
+// CR must have. It is empty when the name is not restricted.
+func RequiredUserConfigMapName(customResource v1alpha1.App) string {
+	// NGINX Ingress Controller is no longer a pre-installed app
+	// managed by cluster-operator. So we don't need to restrict
+	// the name.
+	if CatalogName(customResource) != DefaultCatalogName || AppName(customResource) == NginxIngressControllerAppName {
+		return ""
+	}
+
+	return UserValuesConfigMapName(customResource.Name)
+}
+
+// RequiredUserSecretName returns the name the user secret of the app CR must
+// have. It is empty when the name is not restricted.
+func RequiredUserSecretName(customResource v1alpha1.App) string {
+	if CatalogName(customResource) != DefaultCatalogName {
+		return ""
+	}
+
+	return UserSecretsSecretName(customResource.Name)
+}
+
 func UserConfigMapName(customResource v1alpha1.App) string {
 	return customResource.Spec.UserConfig.ConfigMap.Name
 }
@@ -185,6 +229,18 @@ func UserSecretNamespace(customResource v1alpha1.App) string {
 	return customResource.Spec.UserConfig.Secret.Namespace
 }
 
+// UserSecretsSecretName returns the conventional name of the user secret of
+// the app CR with the given name.
+func UserSecretsSecretName(name string) string {
+	return fmt.Sprintf("%s-user-secrets", name)
+}
+
+// UserValuesConfigMapName returns the conventional name of the user
+// configmap of the app CR with the given name.
+func UserValuesConfigMapName(name string) string {
+	return fmt.Sprintf("%s-user-values", name)
+}
+
 func Version(customResource v1alpha1.App) string {
 	return customResource.Spec.Version
 }
@@ -460,6 +460,62 @@ func Test_ToApp(t *testing.T) {
 	}
 }
 
+func Test_RequiredUserConfigMapName(t *testing.T) {
+	newApp := func(catalog, appName string) v1alpha1.App {
+		return v1alpha1.App{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "my-app",
+			},
+			Spec: v1alpha1.AppSpec{
+				Catalog: catalog,
+				Name:    appName,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                  string
+		obj                   v1alpha1.App
+		expectedConfigMapName string
+		expectedSecretName    string
+	}{
+		{
+			name:                  "case 0: default catalog",
+			obj:                   newApp("default", "kiam"),
+			expectedConfigMapName: "my-app-user-values",
+			expectedSecretName:    "my-app-user-secrets",
+		},
+		{
+			name:                  "case 1: other catalog",
+			obj:                   newApp("giantswarm", "kiam"),
+			expectedConfigMapName: "",
+			expectedSecretName:    "",
+		},
+		{
+			name:                  "case 2: nginx ingress controller in default catalog",
+			obj:                   newApp("default", "nginx-ingress-controller-app"),
+			expectedConfigMapName: "",
+			expectedSecretName:    "my-app-user-secrets",
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Log(tc.name)
+
+			configMapName := RequiredUserConfigMapName(tc.obj)
+			if configMapName != tc.expectedConfigMapName {
+				t.Fatalf("RequiredUserConfigMapName %#q, want %#q", configMapName, tc.expectedConfigMapName)
+			}
+
+			secretName := RequiredUserSecretName(tc.obj)
+			if secretName != tc.expectedSecretName {
+				t.Fatalf("RequiredUserSecretName %#q, want %#q", secretName, tc.expectedSecretName)
+			}
+		})
+	}
+}
+
 func Test_UserConfigMapName(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -7,6 +7,24 @@ import (
 	"github.com/giantswarm/k8smetadata/pkg/annotation"
 )
 
+const (
+	// AppCatalogEntryDeprecatedAnnotation is set to true when the Chart.yaml
+	// of the app this entry belongs to marks the chart as deprecated.
+	AppCatalogEntryDeprecatedAnnotation = "application.giantswarm.io/deprecated"
+	// AppCatalogEntryKubeVersionAnnotation holds the kubeVersion constraint
+	// from the Chart.yaml of the app this entry belongs to.
+	// e.g. >=1.19.0-0
+	AppCatalogEntryKubeVersionAnnotation = "application.giantswarm.io/kube-version"
+)
+
+func AppCatalogEntryDeprecated(customResource v1alpha1.AppCatalogEntry) bool {
+	return customResource.Annotations[AppCatalogEntryDeprecatedAnnotation] == "true"
+}
+
+func AppCatalogEntryKubeVersion(customResource v1alpha1.AppCatalogEntry) string {
+	return customResource.Annotations[AppCatalogEntryKubeVersionAnnotation]
+}
+
 func AppCatalogEntryManagedBy(projectName string) string {
 	return fmt.Sprintf("%s-unique", projectName)
 }
@@ -8,12 +8,11 @@ import (
 
 const (
 	ingressControllerConfigMapName = "ingress-controller-values"
-	nginxIngressControllerAppName  = "nginx-ingress-controller-app"
 )
 
 func ClusterConfigMapName(customResource v1alpha1.App) string {
 	// A separate config map is used for Nginx Ingress Controller.
-	if AppName(customResource) == nginxIngressControllerAppName {
+	if AppName(customResource) == NginxIngressControllerAppName {
 		return ingressControllerConfigMapName
 	}
 
@@ -0,0 +1,24 @@
+package key
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// NamespaceAppQuotaAnnotation limits the number of app CRs in the
+	// namespace.
+	// e.g. 50
+	NamespaceAppQuotaAnnotation = "application.giantswarm.io/app-quota"
+	// NamespaceCatalogAppQuotasAnnotation limits the number of app CRs per
+	// catalog in the namespace. Entries are comma separated.
+	// e.g. giantswarm=20,control-plane-catalog=10
+	NamespaceCatalogAppQuotasAnnotation = "application.giantswarm.io/catalog-app-quotas"
+)
+
+func NamespaceAppQuota(ns corev1.Namespace) string {
+	return ns.Annotations[NamespaceAppQuotaAnnotation]
+}
+
+func NamespaceCatalogAppQuotas(ns corev1.Namespace) string {
+	return ns.Annotations[NamespaceCatalogAppQuotasAnnotation]
+}
@@ -0,0 +1,17 @@
+package key
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// CrossNamespaceReferenceLabel marks configmaps and secrets that app CRs
+	// in other namespaces may reference when the validator uses the opt-in
+	// reference policy.
+	// e.g. true
+	CrossNamespaceReferenceLabel = "application.giantswarm.io/allow-cross-namespace-reference"
+)
+
+func CrossNamespaceReferenceAllowed(obj metav1.Object) bool {
+	return obj.GetLabels()[CrossNamespaceReferenceLabel] == "true"
+}
@@ -3,109 +3,127 @@ package validation
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
 	"github.com/giantswarm/k8smetadata/pkg/label"
 	"github.com/giantswarm/microerror"
-	apierrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/giantswarm/app/v5/pkg/key"
+	"github.com/giantswarm/app/v5/pkg/values"
 )
 
 const (
-	catalogNotFoundTemplate         = "catalog %#q not found"
-	nameTooLongTemplate             = "name %#q is %d chars and exceeds max length of %d chars"
-	namespaceNotFoundReasonTemplate = "namespace is not specified for %s %#q"
-	labelInvalidValueTemplate       = "label %#q has invalid value %#q"
-	labelNotFoundTemplate           = "label %#q not found"
-	resourceNotFoundTemplate        = "%s %#q in namespace %#q not found"
-
-	defaultCatalogName            = "default"
-	nginxIngressControllerAppName = "nginx-ingress-controller-app"
+	catalogQuotaExceededTemplate         = "namespace %#q has %d app CRs from catalog %#q and is limited to %d app CRs from this catalog"
+	catalogNotFoundTemplate              = "catalog %#q not found"
+	clusterSingletonTemplate             = "app %#q can only be installed once in cluster %#q but is already installed by app %#q in namespace %#q"
+	cordonAnnotationMissingTemplate      = "annotation %#q is set without annotation %#q"
+	dependencyNotFoundTemplate           = "app %#q depends on app %#q which is not found in namespace %#q"
+	dependencyVersionTooLowTemplate      = "app %#q depends on app %#q with version %#q or later but found %#q"
+	kubeVersionIncompatibleTemplate      = "app %#q version %#q requires kubernetes version %#q but target cluster has %#q"
+	kubeVersionInvalidConstraintTemplate = "app %#q declares invalid kubernetes version constraint %#q: %s"
+	nameEmptyTemplate                    = "%s must not be empty"
+	nameInvalidTemplate                  = "%s %#q is not a valid %s: %s"
+	nameTooLongTemplate                  = "name %#q is %d chars and exceeds max length of %d chars"
+	namespaceSingletonTemplate           = "app %#q can only be installed only once in namespace %#q of cluster %#q but is already installed by app %#q in namespace %#q"
+	namespaceConfigKeyDeniedTemplate     = "app %#q namespace %s %#q is denied by prefix %#q"
+	namespaceConfigKeyInvalidTemplate    = "app %#q namespace %s key %#q is invalid: %s"
+	namespaceConfigValueInvalidTemplate  = "app %#q namespace %s %#q has invalid value %#q: %s"
+	namespaceNotFoundReasonTemplate      = "namespace is not specified for %s %#q"
+	labelInvalidValueTemplate            = "label %#q has invalid value %#q"
+	policyNotAllowedTemplate             = "app %#q version %#q from catalog %#q in target namespace %#q is not allowed by policy for namespace %#q"
+	kubeConfigInvalidTemplate            = "kubeconfig secret %#q in namespace %#q is invalid: %s"
+	labelNotFoundTemplate                = "label %#q not found"
+	quotaAnnotationInvalidTemplate       = "namespace %#q annotation %#q has invalid value %#q"
+	quotaExceededTemplate                = "namespace %#q has %d app CRs and is limited to %d app CRs"
+	referenceNotAllowedTemplate          = "app %#q in namespace %#q must not reference %s %#q in namespace %#q with reference policy %#q"
+	releaseCollisionTemplate             = "helm release %#q in namespace %#q of cluster %#q is already owned by app %#q in namespace %#q"
+	resourceInvalidTemplate              = "%s %#q in namespace %#q has invalid values: %s"
+	resourceNotFoundTemplate             = "%s %#q in namespace %#q not found"
+
+	appCatalogEntryNotFoundWarningTemplate  = "app %#q version %#q not found in catalog %#q, metadata restrictions are not validated"
+	appCordonedWarningTemplate              = "app %#q is cordoned until %#q with reason %#q and will not be updated"
+	certificateExpiresWarningTemplate       = "kubeconfig secret %#q in namespace %#q %s expires at %s"
+	cordonExpiredWarningTemplate            = "app %#q cordon annotation %#q expired at %#q"
+	releaseCollisionWarningTemplate         = "helm release %#q in namespace %#q of cluster %#q is also installed by app %#q in namespace %#q"
+	appVersionDeprecatedWarningTemplate     = "app %#q version %#q in catalog %#q is deprecated"
+	userConfigOtherNamespaceWarningTemplate = "user %s %#q is in namespace %#q and not in app namespace %#q"
 
 	// nameMaxLength is 53 characters as this is the maximum allowed for Helm
 	// release names.
 	nameMaxLength = 53
 )
 
+// ValidateApp runs the selected rules against the app CR. The first rule
+// that fails rejects the app CR.
 func (v *Validator) ValidateApp(ctx context.Context, app v1alpha1.App) (bool, error) {
-	var err error
-
-	err = v.validateCatalog(ctx, app)
-	if err != nil {
-		return false, microerror.Mask(err)
+	err := v.runRules(ctx, app)
+	if !isReport(ctx) {
+		v.metrics.observeValidation(err)
 	}
-
-	err = v.validateConfig(ctx, app)
 	if err != nil {
 		return false, microerror.Mask(err)
 	}
 
-	err = v.validateKubeConfig(ctx, app)
-	if err != nil {
-		return false, microerror.Mask(err)
-	}
+	return true, nil
+}
 
-	err = v.validateLabels(ctx, app)
-	if err != nil {
-		return false, microerror.Mask(err)
-	}
+func (v *Validator) runRules(ctx context.Context, app v1alpha1.App) error {
+	// Rules share the resources they read, e.g. the catalog.
+	ctx = withLookupCache(ctx)
 
-	err = v.validateMetadataConstraints(ctx, app)
-	if err != nil {
-		return false, microerror.Mask(err)
-	}
+	for _, r := range v.rules {
+		if !r.AppliesTo(app) {
+			continue
+		}
 
-	err = v.validateName(ctx, app)
-	if err != nil {
-		return false, microerror.Mask(err)
+		start := time.Now()
+		err := r.Validate(ctx, app)
+		v.metrics.observeRule(r.Name(), start)
+		if err != nil {
+			return microerror.Mask(err)
+		}
 	}
 
-	err = v.validateNamespaceConfig(ctx, app)
-	if err != nil {
-		return false, microerror.Mask(err)
-	}
+	return nil
+}
 
-	err = v.validateUserConfig(ctx, app)
+// ValidateAppUpdate validates an update of the current app CR to app. It
+// runs the same rules as ValidateAppWithWarnings. Findings that already
+// exist in the current app CR, such as expired cordons, are reported as
+// warnings instead of rejecting the update.
+func (v *Validator) ValidateAppUpdate(ctx context.Context, current, app v1alpha1.App) ([]string, error) {
+	warnings, err := v.ValidateAppWithWarnings(context.WithValue(ctx, currentAppKey{}, current), app)
 	if err != nil {
-		return false, microerror.Mask(err)
+		return warnings, microerror.Mask(err)
 	}
 
-	return true, nil
+	return warnings, nil
 }
 
-func (v *Validator) validateCatalog(ctx context.Context, cr v1alpha1.App) error {
-	var err error
+type currentAppKey struct{}
 
+// currentAppFromContext returns the current app CR when an update is
+// validated.
+func currentAppFromContext(ctx context.Context) (v1alpha1.App, bool) {
+	current, ok := ctx.Value(currentAppKey{}).(v1alpha1.App)
+	return current, ok
+}
+
+func (v *Validator) validateCatalog(ctx context.Context, cr v1alpha1.App) error {
 	if key.CatalogName(cr) == "" {
 		return nil
 	}
 
-	var namespaces []string
-	{
-		if key.CatalogNamespace(cr) != "" {
-			namespaces = []string{key.CatalogNamespace(cr)}
-		} else {
-			namespaces = []string{metav1.NamespaceDefault, "giantswarm"}
-		}
-	}
-
-	var catalog *v1alpha1.Catalog
-
-	for _, ns := range namespaces {
-		catalog, err = v.g8sClient.ApplicationV1alpha1().Catalogs(ns).Get(ctx, key.CatalogName(cr), metav1.GetOptions{})
-		if apierrors.IsNotFound(err) {
-			// no-op
-			continue
-		} else if err != nil {
-			return microerror.Mask(err)
-		}
-		break
+	catalog, err := v.findCatalog(ctx, cr)
+	if err != nil {
+		return microerror.Mask(err)
 	}
 
-	if catalog == nil || catalog.Name == "" {
-		return microerror.Maskf(validationError, catalogNotFoundTemplate, key.CatalogName(cr))
+	if catalog == nil {
+		return resultErrorf(validationError, ReasonCatalogNotFound, "spec.catalog", key.CatalogName(cr), catalogNotFoundTemplate, key.CatalogName(cr))
 	}
 
 	return nil
@@ -115,39 +133,88 @@ func (v *Validator) validateConfig(ctx context.Context, cr v1alpha1.App) error {
 	if key.AppConfigMapName(cr) != "" {
 		ns := key.AppConfigMapNamespace(cr)
 		if ns == "" {
-			return microerror.Maskf(validationError, namespaceNotFoundReasonTemplate, "configmap", key.AppConfigMapName(cr))
+			return resultErrorf(validationError, ReasonNamespaceNotSpecified, "spec.config.configMap.namespace", "", namespaceNotFoundReasonTemplate, "configmap", key.AppConfigMapName(cr))
+		}
+
+		configMap, err := v.getConfigMap(ctx, ns, key.AppConfigMapName(cr))
+		if err != nil {
+			return microerror.Mask(err)
 		}
 
-		_, err := v.k8sClient.CoreV1().ConfigMaps(ns).Get(ctx, key.AppConfigMapName(cr), metav1.GetOptions{})
-		if apierrors.IsNotFound(err) {
+		err = v.validateReference(cr, "spec.config.configMap.namespace", "configmap", key.AppConfigMapName(cr), ns, configMapObject(configMap))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if configMap == nil {
 			// appConfigMapNotFoundError is used rather than a validation error because
 			// during cluster creation there is a short delay while it is generated.
-			return microerror.Maskf(appConfigMapNotFoundError, resourceNotFoundTemplate, "configmap", key.AppConfigMapName(cr), ns)
-		} else if err != nil {
-			return microerror.Mask(err)
+			return resultErrorf(appConfigMapNotFoundError, ReasonResourceNotFound, "spec.config.configMap.name", key.AppConfigMapName(cr), resourceNotFoundTemplate, "configmap", key.AppConfigMapName(cr), ns)
+		}
+
+		_, err = values.ExtractConfigMapData("app", configMap.Data)
+		if err != nil {
+			return resultErrorf(validationError, ReasonResourceInvalid, "spec.config.configMap.name", key.AppConfigMapName(cr), resourceInvalidTemplate, "configmap", key.AppConfigMapName(cr), ns, err)
 		}
 	}
 
 	if key.AppSecretName(cr) != "" {
 		ns := key.AppSecretNamespace(cr)
 		if ns == "" {
-			return microerror.Maskf(validationError, namespaceNotFoundReasonTemplate, "secret", key.AppSecretName(cr))
+			return resultErrorf(validationError, ReasonNamespaceNotSpecified, "spec.config.secret.namespace", "", namespaceNotFoundReasonTemplate, "secret", key.AppSecretName(cr))
 		}
 
-		_, err := v.k8sClient.CoreV1().Secrets(ns).Get(ctx, key.AppSecretName(cr), metav1.GetOptions{})
-		if apierrors.IsNotFound(err) {
-			return microerror.Maskf(validationError, resourceNotFoundTemplate, "secret", key.AppSecretName(cr), ns)
-		} else if err != nil {
+		secret, err := v.getSecret(ctx, ns, key.AppSecretName(cr))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		err = v.validateReference(cr, "spec.config.secret.namespace", "secret", key.AppSecretName(cr), ns, secretObject(secret))
+		if err != nil {
 			return microerror.Mask(err)
 		}
+		if secret == nil {
+			return resultErrorf(validationError, ReasonResourceNotFound, "spec.config.secret.name", key.AppSecretName(cr), resourceNotFoundTemplate, "secret", key.AppSecretName(cr), ns)
+		}
+
+		_, err = values.ExtractSecretData("app", secret.Data)
+		if err != nil {
+			return resultErrorf(validationError, ReasonResourceInvalid, "spec.config.secret.name", key.AppSecretName(cr), resourceInvalidTemplate, "secret", key.AppSecretName(cr), ns, err)
+		}
 	}
 
 	return nil
 }
 
 func (v *Validator) validateName(ctx context.Context, cr v1alpha1.App) error {
+	// metadata.name is used as the name of the chart CR and the Helm release
+	// in the target cluster.
 	if len(cr.Name) > nameMaxLength {
-		return microerror.Maskf(validationError, nameTooLongTemplate, cr.Name, len(cr.Name), nameMaxLength)
+		return resultErrorf(validationError, ReasonNameTooLong, "metadata.name", cr.Name, nameTooLongTemplate, cr.Name, len(cr.Name), nameMaxLength)
+	}
+	if errs := utilvalidation.IsDNS1123Subdomain(cr.Name); len(errs) > 0 {
+		return resultErrorf(validationError, ReasonNameInvalid, "metadata.name", cr.Name, nameInvalidTemplate, "metadata.name", cr.Name, "DNS-1123 subdomain", strings.Join(errs, ", "))
+	}
+
+	// spec.name is used as the Helm release name. Helm requires release
+	// names to be DNS-1123 subdomains of at most 53 characters.
+	if key.ReleaseName(cr) == "" {
+		return resultErrorf(validationError, ReasonNameEmpty, "spec.name", "", nameEmptyTemplate, "spec.name")
+	}
+	if len(key.ReleaseName(cr)) > nameMaxLength {
+		return resultErrorf(validationError, ReasonNameInvalid, "spec.name", key.ReleaseName(cr), nameInvalidTemplate, "spec.name", key.ReleaseName(cr), "Helm release name",
+			utilvalidation.MaxLenError(nameMaxLength))
+	}
+	if errs := utilvalidation.IsDNS1123Subdomain(key.ReleaseName(cr)); len(errs) > 0 {
+		return resultErrorf(validationError, ReasonNameInvalid, "spec.name", key.ReleaseName(cr), nameInvalidTemplate, "spec.name", key.ReleaseName(cr), "Helm release name", strings.Join(errs, ", "))
+	}
+
+	// spec.namespace is the namespace the app is installed in. Namespace
+	// names must be DNS-1123 labels.
+	if key.AppNamespace(cr) == "" {
+		return resultErrorf(validationError, ReasonNameEmpty, "spec.namespace", "", nameEmptyTemplate, "spec.namespace")
+	}
+	if errs := utilvalidation.IsDNS1123Label(key.AppNamespace(cr)); len(errs) > 0 {
+		return resultErrorf(validationError, ReasonNameInvalid, "spec.namespace", key.AppNamespace(cr), nameInvalidTemplate, "spec.namespace", key.AppNamespace(cr), "namespace name", strings.Join(errs, ", "))
 	}
 
 	return nil
@@ -162,21 +229,18 @@ func (v *Validator) validateNamespaceConfig(ctx context.Context, cr v1alpha1.App
 		return nil
 	}
 
-	var apps []v1alpha1.App
-	{
-		lo := metav1.ListOptions{
-			FieldSelector: fmt.Sprintf("metadata.name!=%s", cr.Name),
-		}
-		appList, err := v.g8sClient.ApplicationV1alpha1().Apps(cr.Namespace).List(ctx, lo)
-		if err != nil {
-			return microerror.Mask(err)
-		}
+	err := v.validateNamespaceConfigKeys(cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
 
-		apps = appList.Items
+	apps, err := v.listAppsByTargetNamespace(ctx, cr.Namespace, key.AppNamespace(cr))
+	if err != nil {
+		return microerror.Mask(err)
 	}
 
 	for _, app := range apps {
-		if key.AppNamespace(cr) != key.AppNamespace(app) {
+		if app.Name == cr.Name {
 			continue
 		}
 
@@ -185,7 +249,7 @@ func (v *Validator) validateNamespaceConfig(ctx context.Context, cr v1alpha1.App
 			for k, v := range targetAnnotations {
 				originalValue, ok := annotations[k]
 				if ok && originalValue != v {
-					return microerror.Maskf(validationError, "app %#q annotation %#q for target namespace %#q collides with value %#q for app %#q",
+					return resultErrorf(validationError, ReasonNamespaceConfigCollision, fmt.Sprintf("spec.namespaceConfig.annotations[%s]", k), originalValue, "app %#q annotation %#q for target namespace %#q collides with value %#q for app %#q",
 						key.AppName(cr), k, key.AppNamespace(cr), v, app.Name)
 				}
 			}
@@ -196,7 +260,7 @@ func (v *Validator) validateNamespaceConfig(ctx context.Context, cr v1alpha1.App
 			for k, v := range targetLabels {
 				originalValue, ok := labels[k]
 				if ok && originalValue != v {
-					return microerror.Maskf(validationError, "app %#q label %#q for target namespace %#q collides with value %#q for app %#q",
+					return resultErrorf(validationError, ReasonNamespaceConfigCollision, fmt.Sprintf("spec.namespaceConfig.labels[%s]", k), originalValue, "app %#q label %#q for target namespace %#q collides with value %#q for app %#q",
 						key.AppName(cr), k, key.AppNamespace(cr), v, app.Name)
 				}
 			}
@@ -210,15 +274,26 @@ func (v *Validator) validateKubeConfig(ctx context.Context, cr v1alpha1.App) err
 	if !key.InCluster(cr) {
 		ns := key.KubeConfigSecretNamespace(cr)
 		if ns == "" {
-			return microerror.Maskf(validationError, namespaceNotFoundReasonTemplate, "kubeconfig secret", key.KubeConfigSecretName(cr))
+			return resultErrorf(validationError, ReasonNamespaceNotSpecified, "spec.kubeConfig.secret.namespace", "", namespaceNotFoundReasonTemplate, "kubeconfig secret", key.KubeConfigSecretName(cr))
+		}
+
+		secret, err := v.getSecret(ctx, key.KubeConfigSecretNamespace(cr), key.KubeConfigSecretName(cr))
+		if err != nil {
+			return microerror.Mask(err)
 		}
 
-		_, err := v.k8sClient.CoreV1().Secrets(key.KubeConfigSecretNamespace(cr)).Get(ctx, key.KubeConfigSecretName(cr), metav1.GetOptions{})
-		if apierrors.IsNotFound(err) {
+		err = v.validateReference(cr, "spec.kubeConfig.secret.namespace", "kubeconfig secret", key.KubeConfigSecretName(cr), ns, secretObject(secret))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if secret == nil {
 			// kubeConfigNotFoundError is used rather than a validation error because
 			// during cluster creation there is a short delay while it is generated.
-			return microerror.Maskf(kubeConfigNotFoundError, resourceNotFoundTemplate, "kubeconfig secret", key.KubeConfigSecretName(cr), ns)
-		} else if err != nil {
+			return resultErrorf(kubeConfigNotFoundError, ReasonResourceNotFound, "spec.kubeConfig.secret.name", key.KubeConfigSecretName(cr), resourceNotFoundTemplate, "kubeconfig secret", key.KubeConfigSecretName(cr), ns)
+		}
+
+		err = v.validateKubeConfigData(ctx, cr, secret.Data[key.KubeConfigSecretKey])
+		if err != nil {
 			return microerror.Mask(err)
 		}
 	}
@@ -228,24 +303,32 @@ func (v *Validator) validateKubeConfig(ctx context.Context, cr v1alpha1.App) err
 
 func (v *Validator) validateLabels(ctx context.Context, cr v1alpha1.App) error {
 	if key.VersionLabel(cr) == "" {
-		return microerror.Maskf(validationError, labelNotFoundTemplate, label.AppOperatorVersion)
+		return resultErrorf(validationError, ReasonLabelNotFound, labelField(label.AppOperatorVersion), "", labelNotFoundTemplate, label.AppOperatorVersion)
 	}
 	if key.VersionLabel(cr) == key.LegacyAppVersionLabel {
-		return microerror.Maskf(validationError, labelInvalidValueTemplate, label.AppOperatorVersion, key.VersionLabel(cr))
+		return resultErrorf(validationError, ReasonLabelInvalid, labelField(label.AppOperatorVersion), key.VersionLabel(cr), labelInvalidValueTemplate, label.AppOperatorVersion, key.VersionLabel(cr))
 	}
 
 	return nil
 }
 
 func (v *Validator) validateMetadataConstraints(ctx context.Context, cr v1alpha1.App) error {
-	name := key.AppCatalogEntryName(key.CatalogName(cr), key.AppName(cr), key.Version(cr))
+	entry, err := v.getAppCatalogEntry(ctx, cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
 
-	entry, err := v.g8sClient.ApplicationV1alpha1().AppCatalogEntries(metav1.NamespaceDefault).Get(ctx, name, metav1.GetOptions{})
-	if apierrors.IsNotFound(err) {
+	if entry == nil {
+		name := key.AppCatalogEntryName(key.CatalogName(cr), key.AppName(cr), key.Version(cr))
 		v.logger.Debugf(ctx, "appcatalogentry %#q not found, skipping metadata validation", name)
+		if key.CatalogName(cr) != "" {
+			AddWarning(ctx, appCatalogEntryNotFoundWarningTemplate, key.AppName(cr), key.Version(cr), key.CatalogName(cr))
+		}
 		return nil
-	} else if err != nil {
-		return microerror.Mask(err)
+	}
+
+	if key.AppCatalogEntryDeprecated(*entry) {
+		AddWarning(ctx, appVersionDeprecatedWarningTemplate, key.AppName(cr), key.Version(cr), key.CatalogName(cr))
 	}
 
 	if entry.Spec.Restrictions == nil {
@@ -255,42 +338,41 @@ func (v *Validator) validateMetadataConstraints(ctx context.Context, cr v1alpha1
 
 	if len(entry.Spec.Restrictions.CompatibleProviders) > 0 {
 		if !contains(entry.Spec.Restrictions.CompatibleProviders, v1alpha1.Provider(v.provider)) {
-			return microerror.Maskf(validationError, "app %#q can only be installed for providers %#q not %#q",
+			return resultErrorf(validationError, ReasonProviderNotAllowed, "spec.name", cr.Spec.Name, "app %#q can only be installed for providers %#q not %#q",
 				cr.Spec.Name, entry.Spec.Restrictions.CompatibleProviders, v.provider)
 		}
 	}
 
 	if entry.Spec.Restrictions.FixedNamespace != "" {
 		if entry.Spec.Restrictions.FixedNamespace != cr.Spec.Namespace {
-			return microerror.Maskf(validationError, "app %#q can only be installed in namespace %#q only, not %#q",
+			return resultErrorf(validationError, ReasonNamespaceNotAllowed, "spec.namespace", cr.Spec.Namespace, "app %#q can only be installed in namespace %#q only, not %#q",
 				cr.Spec.Name, entry.Spec.Restrictions.FixedNamespace, cr.Spec.Namespace)
 		}
 	}
 
 	var apps []v1alpha1.App
 	if entry.Spec.Restrictions.ClusterSingleton || entry.Spec.Restrictions.NamespaceSingleton {
-		lo := metav1.ListOptions{
-			FieldSelector: fmt.Sprintf("metadata.name!=%s", cr.Name),
-		}
-		appList, err := v.g8sClient.ApplicationV1alpha1().Apps(cr.Namespace).List(ctx, lo)
+		apps, err = v.listAppsByTargetCluster(ctx, cr)
 		if err != nil {
 			return microerror.Mask(err)
 		}
-
-		apps = appList.Items
 	}
 
+	// Singletons are checked across all namespaces as app CRs in different
+	// namespaces may target the same cluster.
 	for _, app := range apps {
-		if app.Spec.Name == cr.Spec.Name {
-			if entry.Spec.Restrictions.ClusterSingleton {
-				return microerror.Maskf(validationError, "app %#q can only be installed once in cluster %#q",
-					cr.Spec.Name, cr.Namespace)
-			}
-			if entry.Spec.Restrictions.NamespaceSingleton {
-				if app.Spec.Namespace == cr.Spec.Namespace {
-					return microerror.Maskf(validationError, "app %#q can only be installed only once in namespace %#q",
-						cr.Spec.Name, key.Namespace(cr))
-				}
+		if app.Namespace == cr.Namespace && app.Name == cr.Name {
+			continue
+		}
+
+		if entry.Spec.Restrictions.ClusterSingleton {
+			return resultErrorf(validationError, ReasonSingletonViolation, "spec.name", cr.Spec.Name, clusterSingletonTemplate,
+				cr.Spec.Name, targetCluster(cr), app.Name, app.Namespace)
+		}
+		if entry.Spec.Restrictions.NamespaceSingleton {
+			if app.Spec.Namespace == cr.Spec.Namespace {
+				return resultErrorf(validationError, ReasonSingletonViolation, "spec.namespace", cr.Spec.Namespace, namespaceSingletonTemplate,
+					cr.Spec.Name, key.Namespace(cr), targetCluster(cr), app.Name, app.Namespace)
 			}
 		}
 	}
@@ -298,50 +380,77 @@ func (v *Validator) validateMetadataConstraints(ctx context.Context, cr v1alpha1
 	return nil
 }
 
+func (v *Validator) validateUserConfigName(ctx context.Context, cr v1alpha1.App) error {
+	configMapName := key.RequiredUserConfigMapName(cr)
+	if key.UserConfigMapName(cr) != "" && configMapName != "" && key.UserConfigMapName(cr) != configMapName {
+		return resultErrorf(validationError, ReasonUserConfigNameInvalid, "spec.userConfig.configMap.name", key.UserConfigMapName(cr), "user configmap must be named %#q for app in default catalog", configMapName)
+	}
+
+	secretName := key.RequiredUserSecretName(cr)
+	if key.UserSecretName(cr) != "" && secretName != "" && key.UserSecretName(cr) != secretName {
+		return resultErrorf(validationError, ReasonUserConfigNameInvalid, "spec.userConfig.secret.name", key.UserSecretName(cr), "user secret must be named %#q for app in default catalog", secretName)
+	}
+
+	return nil
+}
+
 func (v *Validator) validateUserConfig(ctx context.Context, cr v1alpha1.App) error {
 	if key.UserConfigMapName(cr) != "" {
-		// NGINX Ingress Controller is no longer a pre-installed app
-		// managed by cluster-operator. So we don't need to restrict
-		// the name.
-		if key.CatalogName(cr) == defaultCatalogName && key.AppName(cr) != nginxIngressControllerAppName {
-			configMapName := fmt.Sprintf("%s-user-values", cr.Name)
-			if key.UserConfigMapName(cr) != configMapName {
-				return microerror.Maskf(validationError, "user configmap must be named %#q for app in default catalog", configMapName)
-			}
-		}
-
 		ns := key.UserConfigMapNamespace(cr)
 		if ns == "" {
-			return microerror.Maskf(validationError, namespaceNotFoundReasonTemplate, "configmap", key.UserConfigMapName(cr))
+			return resultErrorf(validationError, ReasonNamespaceNotSpecified, "spec.userConfig.configMap.namespace", "", namespaceNotFoundReasonTemplate, "configmap", key.UserConfigMapName(cr))
+		}
+
+		if ns != cr.Namespace {
+			AddWarning(ctx, userConfigOtherNamespaceWarningTemplate, "configmap", key.UserConfigMapName(cr), ns, cr.Namespace)
 		}
 
-		_, err := v.k8sClient.CoreV1().ConfigMaps(ns).Get(ctx, key.UserConfigMapName(cr), metav1.GetOptions{})
-		if apierrors.IsNotFound(err) {
-			return microerror.Maskf(validationError, resourceNotFoundTemplate, "configmap", key.UserConfigMapName(cr), ns)
-		} else if err != nil {
+		configMap, err := v.getConfigMap(ctx, ns, key.UserConfigMapName(cr))
+		if err != nil {
 			return microerror.Mask(err)
 		}
-	}
 
-	if key.UserSecretName(cr) != "" {
-		if key.CatalogName(cr) == defaultCatalogName {
-			secretName := fmt.Sprintf("%s-user-secrets", cr.Name)
-			if key.UserSecretName(cr) != secretName {
-				return microerror.Maskf(validationError, "user secret must be named %#q for app in default catalog", secretName)
-			}
+		err = v.validateReference(cr, "spec.userConfig.configMap.namespace", "configmap", key.UserConfigMapName(cr), ns, configMapObject(configMap))
+		if err != nil {
+			return microerror.Mask(err)
 		}
+		if configMap == nil {
+			return resultErrorf(validationError, ReasonResourceNotFound, "spec.userConfig.configMap.name", key.UserConfigMapName(cr), resourceNotFoundTemplate, "configmap", key.UserConfigMapName(cr), ns)
+		}
+
+		_, err = values.ExtractConfigMapData("user", configMap.Data)
+		if err != nil {
+			return resultErrorf(validationError, ReasonResourceInvalid, "spec.userConfig.configMap.name", key.UserConfigMapName(cr), resourceInvalidTemplate, "configmap", key.UserConfigMapName(cr), ns, err)
+		}
+	}
 
+	if key.UserSecretName(cr) != "" {
 		ns := key.UserSecretNamespace(cr)
 		if ns == "" {
-			return microerror.Maskf(validationError, namespaceNotFoundReasonTemplate, "secret", key.UserSecretName(cr))
+			return resultErrorf(validationError, ReasonNamespaceNotSpecified, "spec.userConfig.secret.namespace", "", namespaceNotFoundReasonTemplate, "secret", key.UserSecretName(cr))
+		}
+
+		if ns != cr.Namespace {
+			AddWarning(ctx, userConfigOtherNamespaceWarningTemplate, "secret", key.UserSecretName(cr), ns, cr.Namespace)
 		}
 
-		_, err := v.k8sClient.CoreV1().Secrets(key.UserSecretNamespace(cr)).Get(ctx, key.UserSecretName(cr), metav1.GetOptions{})
-		if apierrors.IsNotFound(err) {
-			return microerror.Maskf(validationError, resourceNotFoundTemplate, "secret", key.UserSecretName(cr), ns)
-		} else if err != nil {
+		secret, err := v.getSecret(ctx, key.UserSecretNamespace(cr), key.UserSecretName(cr))
+		if err != nil {
 			return microerror.Mask(err)
 		}
+
+		err = v.validateReference(cr, "spec.userConfig.secret.namespace", "secret", key.UserSecretName(cr), ns, secretObject(secret))
+		if err != nil {
+			return microerror.Mask(err)
+		}
+		if secret == nil {
+			return resultErrorf(validationError, ReasonResourceNotFound, "spec.userConfig.secret.name", key.UserSecretName(cr), resourceNotFoundTemplate, "secret", key.UserSecretName(cr), ns)
+		}
+
+		_, err = values.ExtractSecretData("user", secret.Data)
+		if err != nil {
+			return resultErrorf(validationError, ReasonResourceInvalid, "spec.userConfig.secret.name", key.UserSecretName(cr), resourceInvalidTemplate, "secret", key.UserSecretName(cr), ns, err)
+		}
 	}
 
 	return nil
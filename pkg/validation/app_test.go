@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
 	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
@@ -73,7 +74,7 @@ func Test_ValidateApp(t *testing.T) {
 				newTestConfigMap("kiam-user-values", "eggs2"),
 			},
 			secrets: []*corev1.Secret{
-				newTestSecret("eggs2-kubeconfig", "eggs2"),
+				newTestKubeConfigSecret(t, "eggs2-kubeconfig", "eggs2", "eggs2-kubeconfig", time.Now().Add(365*24*time.Hour)),
 			},
 		},
 		{
@@ -604,6 +605,91 @@ func Test_ValidateApp(t *testing.T) {
 				newTestConfigMap("nginx-ingress-user-values", "eggs2"),
 			},
 		},
+		{
+			name: "case 19: spec.userConfig.configMap has more than one key",
+			obj: v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kiam",
+					Namespace: "eggs2",
+					Labels: map[string]string{
+						label.AppOperatorVersion: "0.0.0",
+					},
+				},
+				Spec: v1alpha1.AppSpec{
+					Catalog:   "giantswarm",
+					Name:      "kiam",
+					Namespace: "kube-system",
+					KubeConfig: v1alpha1.AppSpecKubeConfig{
+						InCluster: true,
+					},
+					UserConfig: v1alpha1.AppSpecUserConfig{
+						ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+							Name:      "kiam-user-values",
+							Namespace: "eggs2",
+						},
+					},
+					Version: "1.4.0",
+				},
+			},
+			catalogs: []*v1alpha1.Catalog{
+				newTestCatalog("giantswarm", "default"),
+			},
+			configMaps: []*corev1.ConfigMap{
+				{
+					Data: map[string]string{
+						"values":       "cluster: yaml\n",
+						"other-values": "cluster: yaml\n",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kiam-user-values",
+						Namespace: "eggs2",
+					},
+				},
+			},
+			expectedErr: "validation error: configmap `kiam-user-values` in namespace `eggs2` has invalid values: parsing error: expected `user` configmap has only one key but got 2",
+		},
+		{
+			name: "case 20: spec.userConfig.secret has invalid YAML",
+			obj: v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kiam",
+					Namespace: "eggs2",
+					Labels: map[string]string{
+						label.AppOperatorVersion: "0.0.0",
+					},
+				},
+				Spec: v1alpha1.AppSpec{
+					Catalog:   "giantswarm",
+					Name:      "kiam",
+					Namespace: "kube-system",
+					KubeConfig: v1alpha1.AppSpecKubeConfig{
+						InCluster: true,
+					},
+					UserConfig: v1alpha1.AppSpecUserConfig{
+						Secret: v1alpha1.AppSpecUserConfigSecret{
+							Name:      "kiam-user-secrets",
+							Namespace: "eggs2",
+						},
+					},
+					Version: "1.4.0",
+				},
+			},
+			catalogs: []*v1alpha1.Catalog{
+				newTestCatalog("giantswarm", "default"),
+			},
+			secrets: []*corev1.Secret{
+				{
+					Data: map[string][]byte{
+						"values": []byte("cluster: yaml\n  broken: [\n"),
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kiam-user-secrets",
+						Namespace: "eggs2",
+					},
+				},
+			},
+			expectedErr: "validation error: secret `kiam-user-secrets` in namespace `eggs2` has invalid values: parsing error: failed to parse `user` secret, logs: error converting YAML to JSON: yaml: line 2",
+		},
 	}
 
 	for _, tc := range tests {
@@ -729,7 +815,13 @@ func Test_ValidateMetadataConstraints(t *testing.T) {
 					Catalog:   "giantswarm",
 					Name:      "kiam",
 					Namespace: "kube-system",
-					Version:   "1.4.0",
+					KubeConfig: v1alpha1.AppSpecKubeConfig{
+						Secret: v1alpha1.AppSpecKubeConfigSecret{
+							Name:      "eggs2-kubeconfig",
+							Namespace: "eggs2",
+						},
+					},
+					Version: "1.4.0",
 				},
 			},
 			apps: []*v1alpha1.App{
@@ -742,7 +834,13 @@ func Test_ValidateMetadataConstraints(t *testing.T) {
 						Catalog:   "giantswarm",
 						Name:      "kiam",
 						Namespace: "giantswarm",
-						Version:   "1.3.0-rc1",
+						KubeConfig: v1alpha1.AppSpecKubeConfig{
+							Secret: v1alpha1.AppSpecKubeConfigSecret{
+								Name:      "eggs2-kubeconfig",
+								Namespace: "eggs2",
+							},
+						},
+						Version: "1.3.0-rc1",
 					},
 				},
 			},
@@ -757,7 +855,7 @@ func Test_ValidateMetadataConstraints(t *testing.T) {
 					},
 				},
 			},
-			expectedErr: "validation error: app `kiam` can only be installed once in cluster `eggs2`",
+			expectedErr: "validation error: app `kiam` can only be installed once in cluster `eggs2/eggs2-kubeconfig` but is already installed by app `another-kiam` in namespace `eggs2`",
 		},
 		{
 			name: "case 3: namespace singleton constraint",
@@ -770,7 +868,13 @@ func Test_ValidateMetadataConstraints(t *testing.T) {
 					Catalog:   "giantswarm",
 					Name:      "kiam",
 					Namespace: "kube-system",
-					Version:   "1.4.0",
+					KubeConfig: v1alpha1.AppSpecKubeConfig{
+						Secret: v1alpha1.AppSpecKubeConfigSecret{
+							Name:      "eggs2-kubeconfig",
+							Namespace: "eggs2",
+						},
+					},
+					Version: "1.4.0",
 				},
 			},
 			apps: []*v1alpha1.App{
@@ -783,7 +887,13 @@ func Test_ValidateMetadataConstraints(t *testing.T) {
 						Catalog:   "giantswarm",
 						Name:      "kiam",
 						Namespace: "giantswarm",
-						Version:   "1.3.0-rc1",
+						KubeConfig: v1alpha1.AppSpecKubeConfig{
+							Secret: v1alpha1.AppSpecKubeConfigSecret{
+								Name:      "eggs2-kubeconfig",
+								Namespace: "eggs2",
+							},
+						},
+						Version: "1.3.0-rc1",
 					},
 				},
 				{
@@ -795,7 +905,13 @@ func Test_ValidateMetadataConstraints(t *testing.T) {
 						Catalog:   "giantswarm",
 						Name:      "kiam",
 						Namespace: "kube-system",
-						Version:   "1.3.0-rc1",
+						KubeConfig: v1alpha1.AppSpecKubeConfig{
+							Secret: v1alpha1.AppSpecKubeConfigSecret{
+								Name:      "eggs2-kubeconfig",
+								Namespace: "eggs2",
+							},
+						},
+						Version: "1.3.0-rc1",
 					},
 				},
 			},
@@ -810,7 +926,7 @@ func Test_ValidateMetadataConstraints(t *testing.T) {
 					},
 				},
 			},
-			expectedErr: "validation error: app `kiam` can only be installed only once in namespace `kube-system`",
+			expectedErr: "validation error: app `kiam` can only be installed only once in namespace `kube-system` of cluster `eggs2/eggs2-kubeconfig` but is already installed by app `another-kiam-1` in namespace `eggs2`",
 		},
 		{
 			name: "case 4: compatible providers constraint",
@@ -883,6 +999,102 @@ func Test_ValidateMetadataConstraints(t *testing.T) {
 	}
 }
 
+func Test_ValidateName(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name            string
+		appName         string
+		releaseName     string
+		targetNamespace string
+		expectedErr     string
+	}{
+		{
+			name:            "case 0: flawless",
+			appName:         "kiam-unique",
+			releaseName:     "kiam",
+			targetNamespace: "kube-system",
+		},
+		{
+			name:            "case 1: metadata.name with upper case characters",
+			appName:         "Kiam",
+			releaseName:     "kiam",
+			targetNamespace: "kube-system",
+			expectedErr:     "validation error: metadata.name `Kiam` is not a valid DNS-1123 subdomain: a DNS-1123 subdomain must consist of lower case alphanumeric characters",
+		},
+		{
+			name:            "case 2: spec.name with underscore",
+			appName:         "kiam",
+			releaseName:     "kiam_app",
+			targetNamespace: "kube-system",
+			expectedErr:     "validation error: spec.name `kiam_app` is not a valid Helm release name: a DNS-1123 subdomain must consist of lower case alphanumeric characters",
+		},
+		{
+			name:            "case 3: spec.name exceeds max length",
+			appName:         "kiam",
+			releaseName:     "kiam-with-a-very-long-release-name-that-helm-will-not-accept",
+			targetNamespace: "kube-system",
+			expectedErr:     "validation error: spec.name `kiam-with-a-very-long-release-name-that-helm-will-not-accept` is not a valid Helm release name: must be no more than 53 characters",
+		},
+		{
+			name:            "case 4: spec.namespace with dot",
+			appName:         "kiam",
+			releaseName:     "kiam",
+			targetNamespace: "kube.system",
+			expectedErr:     "validation error: spec.namespace `kube.system` is not a valid namespace name: a DNS-1123 label must consist of lower case alphanumeric characters",
+		},
+		{
+			name:        "case 5: spec.namespace empty",
+			appName:     "kiam",
+			releaseName: "kiam",
+			expectedErr: "validation error: spec.namespace must not be empty",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      tc.appName,
+					Namespace: "eggs2",
+				},
+				Spec: v1alpha1.AppSpec{
+					Catalog:   "giantswarm",
+					Name:      tc.releaseName,
+					Namespace: tc.targetNamespace,
+					Version:   "1.4.0",
+				},
+			}
+
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(),
+				K8sClient: clientgofake.NewSimpleClientset(),
+				Logger:    microloggertest.New(),
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			err = r.validateName(ctx, obj)
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil && tc.expectedErr != "" {
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+			}
+		})
+	}
+}
+
 func Test_ValidateNamespace(t *testing.T) {
 	ctx := context.Background()
 
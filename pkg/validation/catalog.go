@@ -0,0 +1,140 @@
+package validation
+
+import (
+	"context"
+	"sync"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/microerror"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+// lookupCache memoizes resources read during one validation so rules
+// sharing them, e.g. the catalog and appcatalogentry, read them only once.
+type lookupCache struct {
+	mutex   sync.Mutex
+	objects map[string]interface{}
+}
+
+type lookupCacheKey struct{}
+
+// withLookupCache returns a context memoizing lookups. An existing cache is
+// kept so nested validations share it.
+func withLookupCache(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(lookupCacheKey{}).(*lookupCache); ok {
+		return ctx
+	}
+
+	return context.WithValue(ctx, lookupCacheKey{}, &lookupCache{
+		objects: map[string]interface{}{},
+	})
+}
+
+// lookup returns the memoized result for the key or calls get and memoizes
+// its result. Errors are not memoized. get is always called when the
+// context has no cache.
+func lookup(ctx context.Context, k string, get func() (interface{}, error)) (interface{}, error) {
+	c, ok := ctx.Value(lookupCacheKey{}).(*lookupCache)
+	if !ok {
+		return get()
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if obj, ok := c.objects[k]; ok {
+		return obj, nil
+	}
+
+	obj, err := get()
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	c.objects[k] = obj
+
+	return obj, nil
+}
+
+// findCatalog returns the catalog of the app CR. When the app CR does not
+// specify the catalog namespace the configured catalog namespaces are
+// searched in order. nil is returned if the catalog is not found or the app
+// CR does not specify a catalog.
+func (v *Validator) findCatalog(ctx context.Context, cr v1alpha1.App) (*v1alpha1.Catalog, error) {
+	if key.CatalogName(cr) == "" {
+		return nil, nil
+	}
+
+	obj, err := lookup(ctx, "catalog "+appKey(key.CatalogNamespace(cr), key.CatalogName(cr)), func() (interface{}, error) {
+		return v.searchCatalog(ctx, cr)
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return obj.(*v1alpha1.Catalog), nil
+}
+
+func (v *Validator) searchCatalog(ctx context.Context, cr v1alpha1.App) (*v1alpha1.Catalog, error) {
+	var namespaces []string
+	{
+		if key.CatalogNamespace(cr) != "" {
+			namespaces = []string{key.CatalogNamespace(cr)}
+		} else {
+			namespaces = v.catalogNamespaces
+		}
+	}
+
+	for _, ns := range namespaces {
+		catalog, err := v.getCatalog(ctx, ns, key.CatalogName(cr))
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		if catalog != nil && catalog.Name != "" {
+			return catalog, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// getAppCatalogEntry returns the appcatalogentry for the catalog, app and
+// version of the app CR. nil is returned if the entry is not found or the
+// app CR does not specify a catalog.
+func (v *Validator) getAppCatalogEntry(ctx context.Context, cr v1alpha1.App) (*v1alpha1.AppCatalogEntry, error) {
+	if key.CatalogName(cr) == "" {
+		return nil, nil
+	}
+
+	var namespace string
+	{
+		if v.appCatalogEntryNamespace != "" {
+			namespace = v.appCatalogEntryNamespace
+		} else {
+			// Entries are created in the same namespace as their catalog.
+			catalog, err := v.findCatalog(ctx, cr)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+
+			if catalog != nil {
+				namespace = catalog.Namespace
+			} else {
+				namespace = metav1.NamespaceDefault
+			}
+		}
+	}
+
+	name := key.AppCatalogEntryName(key.CatalogName(cr), key.AppName(cr), key.Version(cr))
+
+	obj, err := lookup(ctx, "appcatalogentry "+appKey(namespace, name), func() (interface{}, error) {
+		return v.getAppCatalogEntryByName(ctx, namespace, name)
+	})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return obj.(*v1alpha1.AppCatalogEntry), nil
+}
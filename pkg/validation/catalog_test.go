@@ -0,0 +1,215 @@
+package validation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func Test_CatalogNamespaces(t *testing.T) {
+	ctx := context.Background()
+
+	obj := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kiam",
+			Namespace: "eggs2",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "acme",
+			Name:      "kiam",
+			Namespace: "kube-system",
+			Version:   "1.4.0",
+		},
+	}
+
+	tests := []struct {
+		name                     string
+		appCatalogEntryNamespace string
+		catalogNamespaces        []string
+		catalogs                 []*v1alpha1.Catalog
+		entryNamespace           string
+		expectedErr              string
+	}{
+		{
+			name:        "case 0: org catalog not found in default namespaces",
+			catalogs:    []*v1alpha1.Catalog{newTestCatalog("acme", "org-acme")},
+			expectedErr: "validation error: catalog `acme` not found",
+		},
+		{
+			name:              "case 1: org catalog found in configured namespaces",
+			catalogNamespaces: []string{"org-acme", "default"},
+			catalogs:          []*v1alpha1.Catalog{newTestCatalog("acme", "org-acme")},
+		},
+		{
+			name:              "case 2: appcatalogentry resolved in catalog namespace",
+			catalogNamespaces: []string{"org-acme", "default"},
+			catalogs:          []*v1alpha1.Catalog{newTestCatalog("acme", "org-acme")},
+			entryNamespace:    "org-acme",
+			expectedErr:       "validation error: app `kiam` can only be installed in namespace `acme-system` only, not `kube-system`",
+		},
+		{
+			name:              "case 3: appcatalogentry outside catalog namespace ignored",
+			catalogNamespaces: []string{"org-acme", "default"},
+			catalogs:          []*v1alpha1.Catalog{newTestCatalog("acme", "org-acme")},
+			entryNamespace:    "default",
+		},
+		{
+			name:                     "case 4: appcatalogentry resolved in configured namespace",
+			appCatalogEntryNamespace: "default",
+			catalogNamespaces:        []string{"org-acme", "default"},
+			catalogs:                 []*v1alpha1.Catalog{newTestCatalog("acme", "org-acme")},
+			entryNamespace:           "default",
+			expectedErr:              "validation error: app `kiam` can only be installed in namespace `acme-system` only, not `kube-system`",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g8sObjs := make([]runtime.Object, 0)
+			for _, cat := range tc.catalogs {
+				g8sObjs = append(g8sObjs, cat)
+			}
+
+			if tc.entryNamespace != "" {
+				g8sObjs = append(g8sObjs, &v1alpha1.AppCatalogEntry{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "acme-kiam-1.4.0",
+						Namespace: tc.entryNamespace,
+					},
+					Spec: v1alpha1.AppCatalogEntrySpec{
+						Restrictions: &v1alpha1.AppCatalogEntrySpecRestrictions{
+							FixedNamespace: "acme-system",
+						},
+					},
+				})
+			}
+
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(g8sObjs...),
+				K8sClient: clientgofake.NewSimpleClientset(),
+				Logger:    microloggertest.New(),
+
+				AppCatalogEntryNamespace: tc.appCatalogEntryNamespace,
+				CatalogNamespaces:        tc.catalogNamespaces,
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			err = r.validateCatalog(ctx, obj)
+			if err == nil {
+				err = r.validateMetadataConstraints(ctx, obj)
+			}
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil && tc.expectedErr != "" {
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+			}
+		})
+	}
+}
+
+func Test_CatalogLookups(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name                string
+		catalog             string
+		expectedCatalogGets int
+		expectedEntryGets   int
+	}{
+		{
+			name: "case 0: app without catalog",
+		},
+		{
+			name:                "case 1: catalog read once",
+			catalog:             "acme",
+			expectedCatalogGets: 2,
+			expectedEntryGets:   1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kiam",
+					Namespace: "eggs2",
+				},
+				Spec: v1alpha1.AppSpec{
+					Catalog:   tc.catalog,
+					Name:      "kiam",
+					Namespace: "kube-system",
+					Version:   "1.4.0",
+				},
+			}
+
+			g8sClient := fake.NewSimpleClientset(newTestCatalog("acme", "giantswarm"))
+
+			// Like the REST client, reject gets without name. The fake
+			// clientset returns not found instead.
+			var catalogGets, entryGets int
+			g8sClient.PrependReactor("get", "*", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				switch action.GetResource().Resource {
+				case "catalogs":
+					catalogGets++
+				case "appcatalogentries":
+					entryGets++
+				}
+
+				if action.(clienttesting.GetAction).GetName() == "" {
+					return true, nil, errors.New("resource name may not be empty")
+				}
+				return false, nil, nil
+			})
+
+			c := Config{
+				G8sClient: g8sClient,
+				K8sClient: clientgofake.NewSimpleClientset(),
+				Logger:    microloggertest.New(),
+
+				EnabledRules:      []string{RuleCatalog, RuleKubeVersion, RuleMetadataConstraints},
+				KubeVersionGetter: &fakeKubeVersionGetter{},
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			_, err = r.ValidateApp(ctx, obj)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			// The catalog is searched in the default and giantswarm
+			// namespaces.
+			if catalogGets != tc.expectedCatalogGets {
+				t.Fatalf("catalog gets == %d, want %d", catalogGets, tc.expectedCatalogGets)
+			}
+			if entryGets != tc.expectedEntryGets {
+				t.Fatalf("appcatalogentry gets == %d, want %d", entryGets, tc.expectedEntryGets)
+			}
+		})
+	}
+}
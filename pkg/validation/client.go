@@ -0,0 +1,255 @@
+package validation
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+// The functions in this file are the only ones reading resources. They use
+// the informer caches when they are synced and the Kubernetes API
+// otherwise. Get functions return nil if the resource is not found. Objects
+// returned from caches are shared and must not be modified.
+
+func (v *Validator) getAppCatalogEntryByName(ctx context.Context, namespace, name string) (*v1alpha1.AppCatalogEntry, error) {
+	if synced(v.appCatalogEntryInformer) {
+		obj, err := getFromCache(v.appCatalogEntryInformer, namespace, name)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		if obj == nil {
+			return nil, nil
+		}
+
+		return obj.(*v1alpha1.AppCatalogEntry), nil
+	}
+
+	v.metrics.observeAPICall("appcatalogentries", "get")
+
+	entry, err := v.g8sClient.ApplicationV1alpha1().AppCatalogEntries(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return entry, nil
+}
+
+func (v *Validator) getCatalog(ctx context.Context, namespace, name string) (*v1alpha1.Catalog, error) {
+	if synced(v.catalogInformer) {
+		obj, err := getFromCache(v.catalogInformer, namespace, name)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		if obj == nil {
+			return nil, nil
+		}
+
+		return obj.(*v1alpha1.Catalog), nil
+	}
+
+	v.metrics.observeAPICall("catalogs", "get")
+
+	catalog, err := v.g8sClient.ApplicationV1alpha1().Catalogs(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return catalog, nil
+}
+
+func (v *Validator) getConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	if synced(v.configMapInformer) {
+		obj, err := getFromCache(v.configMapInformer, namespace, name)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		if obj == nil {
+			return nil, nil
+		}
+
+		return obj.(*corev1.ConfigMap), nil
+	}
+
+	v.metrics.observeAPICall("configmaps", "get")
+
+	configMap, err := v.k8sClient.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return configMap, nil
+}
+
+func (v *Validator) getNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	if synced(v.namespaceInformer) {
+		obj, err := getFromCache(v.namespaceInformer, "", name)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		if obj == nil {
+			return nil, nil
+		}
+
+		return obj.(*corev1.Namespace), nil
+	}
+
+	v.metrics.observeAPICall("namespaces", "get")
+
+	ns, err := v.k8sClient.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return ns, nil
+}
+
+func (v *Validator) getSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	if synced(v.secretInformer) {
+		obj, err := getFromCache(v.secretInformer, namespace, name)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+		if obj == nil {
+			return nil, nil
+		}
+
+		return obj.(*corev1.Secret), nil
+	}
+
+	v.metrics.observeAPICall("secrets", "get")
+
+	secret, err := v.k8sClient.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return secret, nil
+}
+
+// listApps returns the app CRs in the namespace.
+func (v *Validator) listApps(ctx context.Context, namespace string) ([]v1alpha1.App, error) {
+	if namespace != metav1.NamespaceAll {
+		return v.listAppsByIndex(ctx, namespace, cache.NamespaceIndex, namespace)
+	}
+
+	var apps []v1alpha1.App
+
+	if synced(v.appInformer) {
+		for _, obj := range v.appInformer.GetStore().List() {
+			apps = append(apps, *obj.(*v1alpha1.App))
+		}
+
+		return apps, nil
+	}
+
+	v.metrics.observeAPICall("apps", "list")
+
+	appList, err := v.g8sClient.ApplicationV1alpha1().Apps(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return appList.Items, nil
+}
+
+// listAppsByRelease returns the app CRs in all namespaces installing the
+// same Helm release as the app CR.
+func (v *Validator) listAppsByRelease(ctx context.Context, cr v1alpha1.App) ([]v1alpha1.App, error) {
+	return v.listAppsByIndex(ctx, metav1.NamespaceAll, appReleaseIndex, releaseKey(cr))
+}
+
+// listAppsByTargetCluster returns the app CRs in all namespaces installing
+// the same app as the app CR in the same target cluster.
+func (v *Validator) listAppsByTargetCluster(ctx context.Context, cr v1alpha1.App) ([]v1alpha1.App, error) {
+	return v.listAppsByIndex(ctx, metav1.NamespaceAll, appTargetClusterIndex, appKey(targetCluster(cr), key.AppName(cr)))
+}
+
+// listAppsByTargetNamespace returns the app CRs in the namespace installing
+// apps in the target namespace.
+func (v *Validator) listAppsByTargetNamespace(ctx context.Context, namespace, targetNamespace string) ([]v1alpha1.App, error) {
+	return v.listAppsByIndex(ctx, namespace, appTargetNamespaceIndex, appKey(namespace, targetNamespace))
+}
+
+// listAppsByIndex returns the app CRs in the namespace matching the index.
+// Use metav1.NamespaceAll for indexes spanning all namespaces.
+// Without a synced cache the app CRs of the namespace are listed and
+// filtered using the same index function.
+func (v *Validator) listAppsByIndex(ctx context.Context, namespace, indexName, indexedValue string) ([]v1alpha1.App, error) {
+	var apps []v1alpha1.App
+
+	if synced(v.appInformer) {
+		objs, err := v.appInformer.GetIndexer().ByIndex(indexName, indexedValue)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		for _, obj := range objs {
+			apps = append(apps, *obj.(*v1alpha1.App))
+		}
+
+		return apps, nil
+	}
+
+	v.metrics.observeAPICall("apps", "list")
+	if namespace == metav1.NamespaceAll {
+		// App CRs cannot be selected by the indexed spec fields. So all app
+		// CRs are listed which is slow in large clusters.
+		v.metrics.observeClusterWideList(indexName)
+		v.logger.Debugf(ctx, "listing app CRs in all namespaces for index %#q, configure a synced app informer to avoid this", indexName)
+	}
+
+	appList, err := v.g8sClient.ApplicationV1alpha1().Apps(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	indexFunc := appIndexers[indexName]
+	for i := range appList.Items {
+		values, err := indexFunc(&appList.Items[i])
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		if containsString(values, indexedValue) {
+			apps = append(apps, appList.Items[i])
+		}
+	}
+
+	return apps, nil
+}
+
+// getFromCache returns the object with the namespace and name from the
+// informer cache or nil if it does not exist.
+func getFromCache(informer cache.SharedIndexInformer, namespace, name string) (interface{}, error) {
+	key := name
+	if namespace != "" {
+		key = namespace + "/" + name
+	}
+
+	obj, exists, err := informer.GetIndexer().GetByKey(key)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	return obj, nil
+}
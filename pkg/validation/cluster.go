@@ -0,0 +1,24 @@
+package validation
+
+import (
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+const (
+	// inClusterTarget identifies the management cluster as the target
+	// cluster of in-cluster app CRs.
+	inClusterTarget = "in-cluster"
+)
+
+// targetCluster returns a key identifying the cluster the app CR is
+// installed in. App CRs using the same kubeconfig secret target the same
+// cluster whatever namespace they are in.
+func targetCluster(cr v1alpha1.App) string {
+	if key.InCluster(cr) {
+		return inClusterTarget
+	}
+
+	return appKey(key.KubeConfigSecretNamespace(cr), key.KubeConfigSecretName(cr))
+}
@@ -0,0 +1,137 @@
+package validation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_ClusterSingletonAcrossNamespaces(t *testing.T) {
+	ctx := context.Background()
+
+	newApp := func(name, namespace, secretName, secretNamespace string) *v1alpha1.App {
+		app := &v1alpha1.App{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: v1alpha1.AppSpec{
+				Catalog:   "giantswarm",
+				Name:      "kiam",
+				Namespace: "kube-system",
+				Version:   "1.4.0",
+			},
+		}
+
+		if secretName == "" {
+			app.Spec.KubeConfig.InCluster = true
+		} else {
+			app.Spec.KubeConfig.Secret = v1alpha1.AppSpecKubeConfigSecret{
+				Name:      secretName,
+				Namespace: secretNamespace,
+			}
+		}
+
+		return app
+	}
+
+	tests := []struct {
+		name        string
+		obj         *v1alpha1.App
+		apps        []*v1alpha1.App
+		expectedErr string
+	}{
+		{
+			name: "case 0: same kubeconfig secret in another namespace",
+			obj:  newApp("kiam", "org-acme", "eggs2-kubeconfig", "eggs2"),
+			apps: []*v1alpha1.App{
+				newApp("kiam", "eggs2", "eggs2-kubeconfig", "eggs2"),
+			},
+			expectedErr: "validation error: app `kiam` can only be installed once in cluster `eggs2/eggs2-kubeconfig` but is already installed by app `kiam` in namespace `eggs2`",
+		},
+		{
+			name: "case 1: other kubeconfig secret in the same namespace",
+			obj:  newApp("kiam", "eggs2", "eggs2-kubeconfig", "eggs2"),
+			apps: []*v1alpha1.App{
+				newApp("other-kiam", "eggs2", "eggs3-kubeconfig", "eggs3"),
+			},
+		},
+		{
+			name: "case 2: in-cluster apps in different namespaces",
+			obj:  newApp("kiam", "giantswarm", "", ""),
+			apps: []*v1alpha1.App{
+				newApp("kiam", "org-acme", "", ""),
+			},
+			expectedErr: "validation error: app `kiam` can only be installed once in cluster `in-cluster` but is already installed by app `kiam` in namespace `org-acme`",
+		},
+		{
+			name: "case 3: in-cluster and remote cluster",
+			obj:  newApp("kiam", "eggs2", "eggs2-kubeconfig", "eggs2"),
+			apps: []*v1alpha1.App{
+				newApp("kiam", "giantswarm", "", ""),
+			},
+		},
+		{
+			name: "case 4: update of the same app",
+			obj:  newApp("kiam", "eggs2", "eggs2-kubeconfig", "eggs2"),
+			apps: []*v1alpha1.App{
+				newApp("kiam", "eggs2", "eggs2-kubeconfig", "eggs2"),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g8sObjs := []runtime.Object{
+				newTestCatalog("giantswarm", "default"),
+				&v1alpha1.AppCatalogEntry{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "giantswarm-kiam-1.4.0",
+						Namespace: metav1.NamespaceDefault,
+					},
+					Spec: v1alpha1.AppCatalogEntrySpec{
+						Restrictions: &v1alpha1.AppCatalogEntrySpecRestrictions{
+							ClusterSingleton: true,
+						},
+					},
+				},
+			}
+			for _, app := range tc.apps {
+				g8sObjs = append(g8sObjs, app)
+			}
+
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(g8sObjs...),
+				K8sClient: clientgofake.NewSimpleClientset(),
+				Logger:    microloggertest.New(),
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			err = r.validateMetadataConstraints(ctx, *tc.obj)
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil && tc.expectedErr != "" {
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package validation
+
+import (
+	"context"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+const (
+	// cordonUntilMaxZoneOffset is the largest time zone offset from UTC.
+	cordonUntilMaxZoneOffset = 14 * time.Hour
+)
+
+// cordonAnnotations are the reason and until annotations of a cordon. They
+// must be set together.
+var cordonAnnotations = []struct {
+	reason string
+	until  string
+}{
+	{
+		reason: annotation.AppOperatorCordonReason,
+		until:  annotation.AppOperatorCordonUntil,
+	},
+	{
+		reason: annotation.ChartOperatorCordonReason,
+		until:  annotation.ChartOperatorCordonUntil,
+	},
+}
+
+func (v *Validator) validateCordon(ctx context.Context, cr v1alpha1.App) error {
+	current, hasCurrent := currentAppFromContext(ctx)
+
+	for _, c := range cordonAnnotations {
+		reason, reasonOk := cr.Annotations[c.reason]
+		until, untilOk := cr.Annotations[c.until]
+
+		if !reasonOk && !untilOk {
+			continue
+		}
+		if !reasonOk {
+			return resultErrorf(validationError, ReasonCordonInvalid, annotationField(c.reason), "", cordonAnnotationMissingTemplate, c.until, c.reason)
+		}
+		if !untilOk {
+			return resultErrorf(validationError, ReasonCordonInvalid, annotationField(c.until), "", cordonAnnotationMissingTemplate, c.reason, c.until)
+		}
+		if reason == "" {
+			return resultErrorf(validationError, ReasonCordonInvalid, annotationField(c.reason), "", "annotation %#q must not be empty", c.reason)
+		}
+
+		untilDate, err := time.Parse(key.CordonUntilDateLayout, until)
+		if err != nil {
+			return resultErrorf(validationError, ReasonCordonInvalid, annotationField(c.until), until, "annotation %#q value %#q must have format %#q", c.until, until, key.CordonUntilDateLayout)
+		}
+
+		// The layout has no time zone. Dates are UTC but older clients
+		// wrote local time, so only dates in the past in every time zone
+		// are rejected.
+		if untilDate.Add(cordonUntilMaxZoneOffset).Before(time.Now()) {
+			// Cordons expire while the app CR exists. So an expired cordon
+			// that is not changed by the update is only a warning.
+			if hasCurrent && current.Annotations[c.until] == until {
+				AddWarning(ctx, cordonExpiredWarningTemplate, cr.Name, c.until, until)
+				continue
+			}
+
+			return resultErrorf(validationError, ReasonCordonInvalid, annotationField(c.until), until, "annotation %#q value %#q is in the past", c.until, until)
+		}
+
+		AddWarning(ctx, appCordonedWarningTemplate, cr.Name, until, reason)
+	}
+
+	return nil
+}
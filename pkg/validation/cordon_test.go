@@ -0,0 +1,143 @@
+package validation
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+func Test_ValidateCordon(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name               string
+		annotations        map[string]string
+		currentAnnotations map[string]string
+		expectedWarnings   []string
+		expectedErr        string
+	}{
+		{
+			name: "case 0: not cordoned",
+		},
+		{
+			name: "case 1: cordoned until date from key.CordonUntilDate",
+			annotations: map[string]string{
+				annotation.ChartOperatorCordonReason: "manual upgrade",
+				annotation.ChartOperatorCordonUntil:  key.CordonUntilDate(),
+			},
+		},
+		{
+			name: "case 2: until without reason",
+			annotations: map[string]string{
+				annotation.AppOperatorCordonUntil: time.Now().Add(24 * time.Hour).Format(key.CordonUntilDateLayout),
+			},
+			expectedErr: "validation error: annotation `app-operator.giantswarm.io/cordon-until` is set without annotation `app-operator.giantswarm.io/cordon-reason`",
+		},
+		{
+			name: "case 3: reason without until",
+			annotations: map[string]string{
+				annotation.ChartOperatorCordonReason: "manual upgrade",
+			},
+			expectedErr: "validation error: annotation `chart-operator.giantswarm.io/cordon-reason` is set without annotation `chart-operator.giantswarm.io/cordon-until`",
+		},
+		{
+			name: "case 4: until in wrong format",
+			annotations: map[string]string{
+				annotation.AppOperatorCordonReason: "manual upgrade",
+				annotation.AppOperatorCordonUntil:  "2099-01-01",
+			},
+			expectedErr: "validation error: annotation `app-operator.giantswarm.io/cordon-until` value `2099-01-01` must have format `2006-01-02T15:04:05`",
+		},
+		{
+			name: "case 5: until in the past",
+			annotations: map[string]string{
+				annotation.AppOperatorCordonReason: "manual upgrade",
+				annotation.AppOperatorCordonUntil:  "2019-12-31T23:59:59",
+			},
+			expectedErr: "validation error: annotation `app-operator.giantswarm.io/cordon-until` value `2019-12-31T23:59:59` is in the past",
+		},
+		{
+			name: "case 7: until in local time west of UTC",
+			annotations: map[string]string{
+				annotation.AppOperatorCordonReason: "manual upgrade",
+				annotation.AppOperatorCordonUntil:  time.Now().In(time.FixedZone("UTC-5", -5*60*60)).Add(1 * time.Hour).Format(key.CordonUntilDateLayout),
+			},
+		},
+		{
+			name: "case 6: expired cordon of current app CR",
+			annotations: map[string]string{
+				annotation.AppOperatorCordonReason: "manual upgrade",
+				annotation.AppOperatorCordonUntil:  "2019-12-31T23:59:59",
+			},
+			currentAnnotations: map[string]string{
+				annotation.AppOperatorCordonReason: "manual upgrade",
+				annotation.AppOperatorCordonUntil:  "2019-12-31T23:59:59",
+			},
+			expectedWarnings: []string{
+				"app `kiam` cordon annotation `app-operator.giantswarm.io/cordon-until` expired at `2019-12-31T23:59:59`",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "kiam",
+					Namespace:   "eggs2",
+					Annotations: tc.annotations,
+				},
+			}
+
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(),
+				K8sClient: clientgofake.NewSimpleClientset(),
+				Logger:    microloggertest.New(),
+
+				EnabledRules: []string{RuleCordon},
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			var warnings []string
+			if tc.currentAnnotations != nil {
+				current := *obj.DeepCopy()
+				current.Annotations = tc.currentAnnotations
+				warnings, err = r.ValidateAppUpdate(ctx, current, obj)
+			} else {
+				err = r.validateCordon(ctx, obj)
+			}
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil && tc.expectedErr != "" {
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+			}
+
+			if !reflect.DeepEqual(warnings, tc.expectedWarnings) {
+				t.Fatalf("warnings == %#v, want %#v", warnings, tc.expectedWarnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+const (
+	dependencyMinVersionSeparator = ">="
+	dependencySeparator           = ","
+
+	dependsOnField = "metadata.annotations[" + key.AppDependsOnAnnotation + "]"
+)
+
+// Dependency is an app CR that must be installed before the app declaring
+// it. It is declared using the key.AppDependsOnAnnotation annotation.
+type Dependency struct {
+	// Name is the name of the app CR in the same namespace.
+	Name string
+	// MinVersion is optional. When set the app CR must have at least this
+	// version.
+	MinVersion *semver.Version
+}
+
+// ParseDependencies parses the dependencies declared by the app CR.
+func ParseDependencies(cr v1alpha1.App) ([]Dependency, error) {
+	var dependencies []Dependency
+
+	for _, entry := range strings.Split(key.AppDependsOn(cr), dependencySeparator) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		var dependency Dependency
+		{
+			parts := strings.SplitN(entry, dependencyMinVersionSeparator, 2)
+			dependency.Name = strings.TrimSpace(parts[0])
+			if dependency.Name == "" {
+				return nil, resultErrorf(validationError, ReasonDependencyInvalid, dependsOnField, entry, "annotation %#q has dependency %#q without app name", key.AppDependsOnAnnotation, entry)
+			}
+
+			if len(parts) == 2 {
+				version, err := semver.NewVersion(strings.TrimSpace(parts[1]))
+				if err != nil {
+					return nil, resultErrorf(validationError, ReasonDependencyInvalid, dependsOnField, entry, "annotation %#q has dependency %#q with invalid version: %s", key.AppDependsOnAnnotation, entry, err)
+				}
+				dependency.MinVersion = version
+			}
+		}
+
+		dependencies = append(dependencies, dependency)
+	}
+
+	return dependencies, nil
+}
+
+// InstallOrder returns the app CRs sorted so that every app comes after the
+// apps it depends on. Apps without dependencies between them keep their
+// original order. Dependencies on app CRs that are not part of apps are
+// ignored. A dependencyCycleError is returned if the dependencies form a
+// cycle.
+func InstallOrder(apps []v1alpha1.App) ([]v1alpha1.App, error) {
+	index := map[string]int{}
+	for i, app := range apps {
+		index[appKey(app.Namespace, app.Name)] = i
+	}
+
+	dependents := make([][]int, len(apps))
+	inDegree := make([]int, len(apps))
+
+	for i, app := range apps {
+		dependencies, err := ParseDependencies(app)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+
+		for _, dependency := range dependencies {
+			j, ok := index[appKey(app.Namespace, dependency.Name)]
+			if !ok {
+				continue
+			}
+
+			dependents[j] = append(dependents[j], i)
+			inDegree[i]++
+		}
+	}
+
+	var ordered []v1alpha1.App
+	done := make([]bool, len(apps))
+
+	for len(ordered) < len(apps) {
+		next := -1
+		for i := range apps {
+			if !done[i] && inDegree[i] == 0 {
+				next = i
+				break
+			}
+		}
+
+		if next == -1 {
+			var cycle []string
+			for i, app := range apps {
+				if !done[i] {
+					cycle = append(cycle, appKey(app.Namespace, app.Name))
+				}
+			}
+
+			return nil, microerror.Maskf(dependencyCycleError, "apps %#q have cyclic dependencies", cycle)
+		}
+
+		done[next] = true
+		ordered = append(ordered, apps[next])
+
+		for _, i := range dependents[next] {
+			inDegree[i]--
+		}
+	}
+
+	return ordered, nil
+}
+
+func (v *Validator) validateDependencies(ctx context.Context, cr v1alpha1.App) error {
+	dependencies, err := ParseDependencies(cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if len(dependencies) == 0 {
+		// no-op
+		return nil
+	}
+
+	var apps map[string]v1alpha1.App
+	{
+		appList, err := v.listApps(ctx, cr.Namespace)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		apps = map[string]v1alpha1.App{}
+		for _, app := range appList {
+			apps[app.Name] = app
+		}
+	}
+
+	for _, dependency := range dependencies {
+		if dependency.Name == cr.Name {
+			return resultErrorf(validationError, ReasonDependencyInvalid, dependsOnField, dependency.Name, "app %#q cannot depend on itself", cr.Name)
+		}
+
+		app, ok := apps[dependency.Name]
+		if !ok {
+			return resultErrorf(validationError, ReasonDependencyNotFound, dependsOnField, dependency.Name, dependencyNotFoundTemplate, cr.Name, dependency.Name, cr.Namespace)
+		}
+
+		if dependency.MinVersion == nil {
+			continue
+		}
+
+		version, err := semver.NewVersion(key.Version(app))
+		if err != nil {
+			return resultErrorf(validationError, ReasonDependencyInvalid, dependsOnField, dependency.Name, "app %#q depends on app %#q which has invalid version %#q", cr.Name, dependency.Name, key.Version(app))
+		}
+
+		if version.LessThan(dependency.MinVersion) {
+			return resultErrorf(validationError, ReasonDependencyVersionTooLow, dependsOnField, dependency.Name, dependencyVersionTooLowTemplate, cr.Name, dependency.Name, dependency.MinVersion.Original(), key.Version(app))
+		}
+	}
+
+	// Only cycles reachable from the app CR are checked. Cycles among other
+	// app CRs in the namespace do not affect its installation.
+	reachable := reachableDependencies(cr, apps)
+	sort.Slice(reachable, func(i, j int) bool {
+		return reachable[i].Name < reachable[j].Name
+	})
+
+	_, err = InstallOrder(append([]v1alpha1.App{cr}, reachable...))
+	if IsDependencyCycle(err) {
+		return resultErrorf(validationError, ReasonDependencyCycle, dependsOnField, key.AppDependsOn(cr), "app %#q dependencies form a cycle: %s", cr.Name, err)
+	} else if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// reachableDependencies returns the app CRs the app CR depends on directly
+// or transitively. App CRs with invalid dependency annotations are skipped
+// as they are rejected when they are validated themselves.
+func reachableDependencies(cr v1alpha1.App, apps map[string]v1alpha1.App) []v1alpha1.App {
+	var reachable []v1alpha1.App
+
+	visited := map[string]bool{cr.Name: true}
+	queue := []v1alpha1.App{cr}
+
+	for len(queue) > 0 {
+		app := queue[0]
+		queue = queue[1:]
+
+		dependencies, err := ParseDependencies(app)
+		if err != nil {
+			continue
+		}
+
+		for _, dependency := range dependencies {
+			if visited[dependency.Name] {
+				continue
+			}
+			visited[dependency.Name] = true
+
+			next, ok := apps[dependency.Name]
+			if !ok {
+				continue
+			}
+
+			_, err = ParseDependencies(next)
+			if err != nil {
+				continue
+			}
+
+			reachable = append(reachable, next)
+			queue = append(queue, next)
+		}
+	}
+
+	return reachable
+}
+
+func appKey(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
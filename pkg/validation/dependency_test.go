@@ -0,0 +1,212 @@
+package validation
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+func Test_InstallOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		apps          []v1alpha1.App
+		expectedOrder []string
+		errorMatcher  func(error) bool
+	}{
+		{
+			name: "case 0: no dependencies keeps order",
+			apps: []v1alpha1.App{
+				newTestDependencyApp("kiam", "eggs2", "1.4.0", ""),
+				newTestDependencyApp("cert-manager", "eggs2", "2.4.0", ""),
+			},
+			expectedOrder: []string{"kiam", "cert-manager"},
+		},
+		{
+			name: "case 1: dependencies are installed first",
+			apps: []v1alpha1.App{
+				newTestDependencyApp("kiam", "eggs2", "1.4.0", "cert-manager>=2.0.0"),
+				newTestDependencyApp("external-dns", "eggs2", "2.1.0", "kiam,cert-manager"),
+				newTestDependencyApp("cert-manager", "eggs2", "2.4.0", ""),
+			},
+			expectedOrder: []string{"cert-manager", "kiam", "external-dns"},
+		},
+		{
+			name: "case 2: dependencies on other apps are ignored",
+			apps: []v1alpha1.App{
+				newTestDependencyApp("kiam", "eggs2", "1.4.0", "cert-manager"),
+			},
+			expectedOrder: []string{"kiam"},
+		},
+		{
+			name: "case 3: cycle is reported",
+			apps: []v1alpha1.App{
+				newTestDependencyApp("kiam", "eggs2", "1.4.0", "cert-manager"),
+				newTestDependencyApp("cert-manager", "eggs2", "2.4.0", "kiam"),
+			},
+			errorMatcher: IsDependencyCycle,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ordered, err := InstallOrder(tc.apps)
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if tc.errorMatcher != nil {
+				return
+			}
+
+			var names []string
+			for _, app := range ordered {
+				names = append(names, app.Name)
+			}
+
+			if !reflect.DeepEqual(names, tc.expectedOrder) {
+				t.Fatalf("order == %#v, want %#v", names, tc.expectedOrder)
+			}
+		})
+	}
+}
+
+func Test_ValidateDependencies(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		obj         v1alpha1.App
+		apps        []v1alpha1.App
+		expectedErr string
+	}{
+		{
+			name: "case 0: flawless",
+			obj:  newTestDependencyApp("kiam", "eggs2", "1.4.0", "cert-manager>=2.0.0, external-dns"),
+			apps: []v1alpha1.App{
+				newTestDependencyApp("cert-manager", "eggs2", "2.4.0", ""),
+				newTestDependencyApp("external-dns", "eggs2", "2.1.0", ""),
+			},
+		},
+		{
+			name: "case 1: dependency in other namespace",
+			obj:  newTestDependencyApp("kiam", "eggs2", "1.4.0", "cert-manager"),
+			apps: []v1alpha1.App{
+				newTestDependencyApp("cert-manager", "eggs1", "2.4.0", ""),
+			},
+			expectedErr: "validation error: app `kiam` depends on app `cert-manager` which is not found in namespace `eggs2`",
+		},
+		{
+			name: "case 2: dependency version too low",
+			obj:  newTestDependencyApp("kiam", "eggs2", "1.4.0", "cert-manager>=2.0.0"),
+			apps: []v1alpha1.App{
+				newTestDependencyApp("cert-manager", "eggs2", "1.9.0", ""),
+			},
+			expectedErr: "validation error: app `kiam` depends on app `cert-manager` with version `2.0.0` or later but found `1.9.0`",
+		},
+		{
+			name:        "case 3: invalid annotation",
+			obj:         newTestDependencyApp("kiam", "eggs2", "1.4.0", "cert-manager>=two"),
+			expectedErr: "validation error: annotation `app-operator.giantswarm.io/depends-on` has dependency `cert-manager>=two` with invalid version",
+		},
+		{
+			name: "case 4: dependency cycle",
+			obj:  newTestDependencyApp("kiam", "eggs2", "1.4.0", "cert-manager"),
+			apps: []v1alpha1.App{
+				newTestDependencyApp("cert-manager", "eggs2", "2.4.0", "kiam"),
+			},
+			expectedErr: "validation error: app `kiam` dependencies form a cycle",
+		},
+		{
+			name: "case 5: cycle of other apps is ignored",
+			obj:  newTestDependencyApp("kiam", "eggs2", "1.4.0", "cert-manager"),
+			apps: []v1alpha1.App{
+				newTestDependencyApp("cert-manager", "eggs2", "2.4.0", ""),
+				newTestDependencyApp("external-dns", "eggs2", "2.1.0", "nginx"),
+				newTestDependencyApp("nginx", "eggs2", "1.0.0", "external-dns"),
+			},
+		},
+		{
+			name: "case 6: cycle of transitive dependencies",
+			obj:  newTestDependencyApp("kiam", "eggs2", "1.4.0", "cert-manager"),
+			apps: []v1alpha1.App{
+				newTestDependencyApp("cert-manager", "eggs2", "2.4.0", "external-dns"),
+				newTestDependencyApp("external-dns", "eggs2", "2.1.0", "nginx"),
+				newTestDependencyApp("nginx", "eggs2", "1.0.0", "external-dns"),
+			},
+			expectedErr: "validation error: app `kiam` dependencies form a cycle",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g8sObjs := make([]runtime.Object, 0)
+			for i := range tc.apps {
+				g8sObjs = append(g8sObjs, &tc.apps[i])
+			}
+
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(g8sObjs...),
+				K8sClient: clientgofake.NewSimpleClientset(),
+				Logger:    microloggertest.New(),
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			err = r.validateDependencies(ctx, tc.obj)
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil && tc.expectedErr != "" {
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+			}
+		})
+	}
+}
+
+func newTestDependencyApp(name, namespace, version, dependsOn string) v1alpha1.App {
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "giantswarm",
+			Name:      name,
+			Namespace: "kube-system",
+			Version:   version,
+		},
+	}
+
+	if dependsOn != "" {
+		app.Annotations = map[string]string{
+			key.AppDependsOnAnnotation: dependsOn,
+		}
+	}
+
+	return app
+}
@@ -39,6 +39,15 @@ func IsAppConfigMapNotFound(err error) bool {
 	return false
 }
 
+var dependencyCycleError = &microerror.Error{
+	Kind: "dependencyCycleError",
+}
+
+// IsDependencyCycle asserts dependencyCycleError.
+func IsDependencyCycle(err error) bool {
+	return microerror.Cause(err) == dependencyCycleError
+}
+
 var invalidConfigError = &microerror.Error{
 	Kind: "invalidConfigError",
 }
@@ -48,6 +57,24 @@ func IsInvalidConfig(err error) bool {
 	return microerror.Cause(err) == invalidConfigError
 }
 
+var invalidManifestError = &microerror.Error{
+	Kind: "invalidManifestError",
+}
+
+// IsInvalidManifest asserts invalidManifestError.
+func IsInvalidManifest(err error) bool {
+	return microerror.Cause(err) == invalidManifestError
+}
+
+var invalidPolicyError = &microerror.Error{
+	Kind: "invalidPolicyError",
+}
+
+// IsInvalidPolicy asserts invalidPolicyError.
+func IsInvalidPolicy(err error) bool {
+	return microerror.Cause(err) == invalidPolicyError
+}
+
 var kubeConfigNotFoundError = &microerror.Error{
 	Kind: "kubeConfigNotFoundError",
 }
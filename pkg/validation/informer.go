@@ -0,0 +1,138 @@
+package validation
+
+import (
+	"context"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned"
+	"github.com/giantswarm/microerror"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+const (
+	// appTargetClusterIndex indexes app CRs by target cluster and app
+	// name. It is used by the singleton checks.
+	appTargetClusterIndex = "application.giantswarm.io/target-cluster"
+	// appReleaseIndex indexes app CRs by the Helm release they install. It
+	// is used by the release collision check.
+	appReleaseIndex = "application.giantswarm.io/release"
+	// appTargetNamespaceIndex indexes app CRs by namespace and target
+	// namespace. It is used by the namespace config collision check.
+	appTargetNamespaceIndex = "application.giantswarm.io/target-namespace"
+)
+
+// appIndexers are added to Config.AppInformer by NewValidator.
+var appIndexers = cache.Indexers{
+	appReleaseIndex:         appReleaseIndexFunc,
+	appTargetClusterIndex:   appTargetClusterIndexFunc,
+	appTargetNamespaceIndex: appTargetNamespaceIndexFunc,
+	cache.NamespaceIndex:    cache.MetaNamespaceIndexFunc,
+}
+
+// NewAppInformer returns an informer for app CRs in all namespaces that can
+// be used as Config.AppInformer.
+func NewAppInformer(client versioned.Interface, resync time.Duration) cache.SharedIndexInformer {
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return client.ApplicationV1alpha1().Apps(metav1.NamespaceAll).List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return client.ApplicationV1alpha1().Apps(metav1.NamespaceAll).Watch(context.Background(), options)
+		},
+	}
+
+	return cache.NewSharedIndexInformer(lw, &v1alpha1.App{}, resync, cache.Indexers{})
+}
+
+// NewAppCatalogEntryInformer returns an informer for appcatalogentry CRs in
+// all namespaces that can be used as Config.AppCatalogEntryInformer.
+func NewAppCatalogEntryInformer(client versioned.Interface, resync time.Duration) cache.SharedIndexInformer {
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return client.ApplicationV1alpha1().AppCatalogEntries(metav1.NamespaceAll).List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return client.ApplicationV1alpha1().AppCatalogEntries(metav1.NamespaceAll).Watch(context.Background(), options)
+		},
+	}
+
+	return cache.NewSharedIndexInformer(lw, &v1alpha1.AppCatalogEntry{}, resync, cache.Indexers{})
+}
+
+// NewCatalogInformer returns an informer for catalog CRs in all namespaces
+// that can be used as Config.CatalogInformer.
+func NewCatalogInformer(client versioned.Interface, resync time.Duration) cache.SharedIndexInformer {
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return client.ApplicationV1alpha1().Catalogs(metav1.NamespaceAll).List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return client.ApplicationV1alpha1().Catalogs(metav1.NamespaceAll).Watch(context.Background(), options)
+		},
+	}
+
+	return cache.NewSharedIndexInformer(lw, &v1alpha1.Catalog{}, resync, cache.Indexers{})
+}
+
+// addAppIndexers adds the indexes used by the validator to the app
+// informer. Indexes that already exist, e.g. because the informer is shared
+// by several validators, are kept.
+func addAppIndexers(informer cache.SharedIndexInformer) error {
+	existing := informer.GetIndexer().GetIndexers()
+
+	indexers := cache.Indexers{}
+	for name, f := range appIndexers {
+		if _, ok := existing[name]; !ok {
+			indexers[name] = f
+		}
+	}
+
+	if len(indexers) == 0 {
+		return nil
+	}
+
+	err := informer.AddIndexers(indexers)
+	if err != nil {
+		return microerror.Maskf(invalidConfigError, "app informer indexes cannot be added: %s", err)
+	}
+
+	return nil
+}
+
+func appTargetClusterIndexFunc(obj interface{}) ([]string, error) {
+	app, ok := obj.(*v1alpha1.App)
+	if !ok {
+		return nil, nil
+	}
+
+	return []string{appKey(targetCluster(*app), key.AppName(*app))}, nil
+}
+
+func appReleaseIndexFunc(obj interface{}) ([]string, error) {
+	app, ok := obj.(*v1alpha1.App)
+	if !ok {
+		return nil, nil
+	}
+
+	return []string{releaseKey(*app)}, nil
+}
+
+func appTargetNamespaceIndexFunc(obj interface{}) ([]string, error) {
+	app, ok := obj.(*v1alpha1.App)
+	if !ok {
+		return nil, nil
+	}
+
+	return []string{appKey(app.Namespace, key.AppNamespace(*app))}, nil
+}
+
+// synced returns whether the informer is set and its cache can be used.
+func synced(informer cache.SharedIndexInformer) bool {
+	return informer != nil && informer.HasSynced()
+}
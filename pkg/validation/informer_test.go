@@ -0,0 +1,186 @@
+package validation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/k8smetadata/pkg/label"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func Test_Informers(t *testing.T) {
+	ctx := context.Background()
+
+	newApp := func(name, appName, targetNamespace string, namespaceLabels map[string]string) *v1alpha1.App {
+		return &v1alpha1.App{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "eggs2",
+				Labels: map[string]string{
+					label.AppOperatorVersion: "0.0.0",
+				},
+			},
+			Spec: v1alpha1.AppSpec{
+				Catalog:   "giantswarm",
+				Name:      appName,
+				Namespace: targetNamespace,
+				KubeConfig: v1alpha1.AppSpecKubeConfig{
+					InCluster: true,
+				},
+				NamespaceConfig: v1alpha1.AppSpecNamespaceConfig{
+					Labels: namespaceLabels,
+				},
+				UserConfig: v1alpha1.AppSpecUserConfig{
+					ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+						Name:      name + "-user-values",
+						Namespace: "eggs2",
+					},
+				},
+				Version: "1.4.0",
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		obj         *v1alpha1.App
+		expectedErr string
+	}{
+		{
+			name: "case 0: flawless",
+			obj:  newApp("kiam", "kiam", "kube-system", map[string]string{"monitoring": "enabled"}),
+		},
+		{
+			name:        "case 1: singleton already installed",
+			obj:         newApp("kiam-2", "kiam", "kiam", nil),
+			expectedErr: "validation error: app `kiam` can only be installed once in cluster `in-cluster` but is already installed by app `kiam` in namespace `eggs2`",
+		},
+		{
+			name:        "case 2: namespace label collision",
+			obj:         newApp("cert-manager", "cert-manager", "kube-system", map[string]string{"monitoring": "disabled"}),
+			expectedErr: "validation error: app `cert-manager` label `monitoring` for target namespace `kube-system` collides with value `enabled` for app `kiam`",
+		},
+	}
+
+	g8sObjs := []runtime.Object{
+		newApp("kiam", "kiam", "kube-system", map[string]string{"monitoring": "enabled"}),
+		newTestCatalog("giantswarm", "default"),
+		&v1alpha1.AppCatalogEntry{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "giantswarm-kiam-1.4.0",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: v1alpha1.AppCatalogEntrySpec{
+				Restrictions: &v1alpha1.AppCatalogEntrySpecRestrictions{
+					ClusterSingleton: true,
+				},
+			},
+		},
+	}
+	k8sObjs := []runtime.Object{
+		newTestConfigMap("kiam-user-values", "eggs2"),
+		newTestConfigMap("kiam-2-user-values", "eggs2"),
+		newTestConfigMap("cert-manager-user-values", "eggs2"),
+	}
+
+	g8sClient := fake.NewSimpleClientset(g8sObjs...)
+	k8sClient := clientgofake.NewSimpleClientset(k8sObjs...)
+	factory := informers.NewSharedInformerFactory(k8sClient, 0)
+
+	appInformer := NewAppInformer(g8sClient, 0)
+	entryInformer := NewAppCatalogEntryInformer(g8sClient, 0)
+	catalogInformer := NewCatalogInformer(g8sClient, 0)
+	configMapInformer := factory.Core().V1().ConfigMaps().Informer()
+	secretInformer := factory.Core().V1().Secrets().Informer()
+
+	registry := prometheus.NewRegistry()
+
+	c := Config{
+		G8sClient: g8sClient,
+		K8sClient: k8sClient,
+		Logger:    microloggertest.New(),
+
+		AppInformer:             appInformer,
+		AppCatalogEntryInformer: entryInformer,
+		CatalogInformer:         catalogInformer,
+		ConfigMapInformer:       configMapInformer,
+		SecretInformer:          secretInformer,
+		Registerer:              registry,
+
+		Provider: "aws",
+	}
+	r, err := NewValidator(c)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	for _, informer := range []cache.SharedIndexInformer{appInformer, entryInformer, catalogInformer, configMapInformer, secretInformer} {
+		go informer.Run(stopCh)
+	}
+	if !cache.WaitForCacheSync(stopCh, appInformer.HasSynced, entryInformer.HasSynced, catalogInformer.HasSynced, configMapInformer.HasSynced, secretInformer.HasSynced) {
+		t.Fatalf("caches not synced")
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := r.ValidateApp(ctx, *tc.obj)
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil && tc.expectedErr != "" {
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+			}
+		})
+	}
+
+	t.Run("no api calls", func(t *testing.T) {
+		for _, resource := range []string{"appcatalogentries", "catalogs", "configmaps", "secrets"} {
+			if value := testutil.ToFloat64(r.metrics.apiCalls.WithLabelValues(resource, "get")); value != 0 {
+				t.Fatalf("%s get calls == %v, want 0", resource, value)
+			}
+		}
+		if value := testutil.ToFloat64(r.metrics.apiCalls.WithLabelValues("apps", "list")); value != 0 {
+			t.Fatalf("apps list calls == %v, want 0", value)
+		}
+	})
+
+	t.Run("shared app informer", func(t *testing.T) {
+		c.Registerer = nil
+
+		// The indexes already exist so they are not added again.
+		_, err := NewValidator(c)
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+	})
+
+	t.Run("started app informer", func(t *testing.T) {
+		c.AppInformer = NewAppInformer(g8sClient, 0)
+		go c.AppInformer.Run(stopCh)
+		cache.WaitForCacheSync(stopCh, c.AppInformer.HasSynced)
+
+		_, err := NewValidator(c)
+		if !IsInvalidConfig(err) {
+			t.Fatalf("error == %#v, want invalid config error", err)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package validation
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+const (
+	// defaultCertificateExpiryWarningPeriod is how long before expiry a
+	// warning is returned for kubeconfig certificates.
+	defaultCertificateExpiryWarningPeriod = 7 * 24 * time.Hour
+)
+
+// validateKubeConfigData checks the kubeconfig of the app CR without
+// connecting to the remote cluster.
+func (v *Validator) validateKubeConfigData(ctx context.Context, cr v1alpha1.App, data []byte) error {
+	if len(data) == 0 {
+		return kubeConfigInvalidError(cr, "key %#q not found", key.KubeConfigSecretKey)
+	}
+
+	kubeConfig, err := clientcmd.Load(data)
+	if err != nil {
+		return kubeConfigInvalidError(cr, "%s", err)
+	}
+
+	contextName := key.KubeConfigContextName(cr)
+	if contextName == "" {
+		contextName = kubeConfig.CurrentContext
+	}
+	if contextName == "" {
+		return kubeConfigInvalidError(cr, "current context is not set")
+	}
+
+	kubeContext, ok := kubeConfig.Contexts[contextName]
+	if !ok {
+		return kubeConfigInvalidError(cr, "context %#q not found", contextName)
+	}
+
+	cluster, ok := kubeConfig.Clusters[kubeContext.Cluster]
+	if !ok {
+		return kubeConfigInvalidError(cr, "cluster %#q of context %#q not found", kubeContext.Cluster, contextName)
+	}
+
+	u, err := url.Parse(cluster.Server)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return kubeConfigInvalidError(cr, "cluster %#q has invalid server %#q", kubeContext.Cluster, cluster.Server)
+	}
+	if cluster.CertificateAuthority != "" {
+		return kubeConfigInvalidError(cr, "cluster %#q must embed certificate authority data instead of file %#q", kubeContext.Cluster, cluster.CertificateAuthority)
+	}
+	if len(cluster.CertificateAuthorityData) > 0 {
+		err = v.validateCertificates(ctx, cr, fmt.Sprintf("cluster %#q certificate authority", kubeContext.Cluster), cluster.CertificateAuthorityData)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	authInfo, ok := kubeConfig.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return kubeConfigInvalidError(cr, "user %#q of context %#q not found", kubeContext.AuthInfo, contextName)
+	}
+
+	err = v.validateAuthInfo(ctx, cr, kubeContext.AuthInfo, authInfo)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
+
+// validateAuthInfo checks that the user has embedded credentials.
+func (v *Validator) validateAuthInfo(ctx context.Context, cr v1alpha1.App, name string, authInfo *clientcmdapi.AuthInfo) error {
+	if authInfo.ClientCertificate != "" || authInfo.ClientKey != "" || authInfo.TokenFile != "" {
+		return kubeConfigInvalidError(cr, "user %#q must embed credentials instead of referencing files", name)
+	}
+
+	if len(authInfo.ClientCertificateData) > 0 || len(authInfo.ClientKeyData) > 0 {
+		_, err := tls.X509KeyPair(authInfo.ClientCertificateData, authInfo.ClientKeyData)
+		if err != nil {
+			return kubeConfigInvalidError(cr, "user %#q has invalid client certificate or key: %s", name, err)
+		}
+
+		err = v.validateCertificates(ctx, cr, fmt.Sprintf("user %#q client certificate", name), authInfo.ClientCertificateData)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+
+		return nil
+	}
+
+	if authInfo.Token != "" || authInfo.Username != "" || authInfo.Exec != nil || authInfo.AuthProvider != nil {
+		return nil
+	}
+
+	return kubeConfigInvalidError(cr, "user %#q has no credentials", name)
+}
+
+// validateCertificates checks that the PEM data holds certificates that have
+// not expired. A warning is added for certificates that expire soon.
+func (v *Validator) validateCertificates(ctx context.Context, cr v1alpha1.App, description string, data []byte) error {
+	var count int
+
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return kubeConfigInvalidError(cr, "%s is invalid: %s", description, err)
+		}
+		count++
+
+		now := time.Now()
+		if now.After(cert.NotAfter) {
+			return kubeConfigInvalidError(cr, "%s expired at %s", description, cert.NotAfter.UTC().Format(time.RFC3339))
+		}
+		if now.Add(v.certificateExpiryWarningPeriod).After(cert.NotAfter) {
+			AddWarning(ctx, certificateExpiresWarningTemplate, key.KubeConfigSecretName(cr), key.KubeConfigSecretNamespace(cr), description, cert.NotAfter.UTC().Format(time.RFC3339))
+		}
+	}
+
+	if count == 0 {
+		return kubeConfigInvalidError(cr, "%s has no PEM encoded certificates", description)
+	}
+
+	return nil
+}
+
+func kubeConfigInvalidError(cr v1alpha1.App, format string, args ...interface{}) error {
+	return resultErrorf(validationError, ReasonKubeConfigInvalid, "spec.kubeConfig.secret.name", key.KubeConfigSecretName(cr), kubeConfigInvalidTemplate, key.KubeConfigSecretName(cr), key.KubeConfigSecretNamespace(cr), fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,192 @@
+package validation
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+func Test_ValidateKubeConfig(t *testing.T) {
+	ctx := context.Background()
+
+	validUntil := time.Now().Add(365 * 24 * time.Hour)
+
+	tests := []struct {
+		name             string
+		secret           *corev1.Secret
+		warningPeriod    time.Duration
+		expectedWarnings []string
+		expectedErr      string
+	}{
+		{
+			name:   "case 0: flawless",
+			secret: newTestKubeConfigSecret(t, "eggs2-kubeconfig", "eggs2", "eggs2-kubeconfig", validUntil),
+		},
+		{
+			name:        "case 1: missing kubeconfig key",
+			secret:      newTestSecret("eggs2-kubeconfig", "eggs2"),
+			expectedErr: "validation error: kubeconfig secret `eggs2-kubeconfig` in namespace `eggs2` is invalid: key `kubeConfig` not found",
+		},
+		{
+			name: "case 2: unparseable kubeconfig",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					key.KubeConfigSecretKey: []byte("clusters: {"),
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "eggs2-kubeconfig",
+					Namespace: "eggs2",
+				},
+			},
+			expectedErr: "validation error: kubeconfig secret `eggs2-kubeconfig` in namespace `eggs2` is invalid: ",
+		},
+		{
+			name:        "case 3: context not found",
+			secret:      newTestKubeConfigSecret(t, "eggs2-kubeconfig", "eggs2", "other-context", validUntil),
+			expectedErr: "validation error: kubeconfig secret `eggs2-kubeconfig` in namespace `eggs2` is invalid: context `eggs2-kubeconfig` not found",
+		},
+		{
+			name:        "case 4: expired certificate",
+			secret:      newTestKubeConfigSecret(t, "eggs2-kubeconfig", "eggs2", "eggs2-kubeconfig", time.Now().Add(-time.Hour)),
+			expectedErr: "validation error: kubeconfig secret `eggs2-kubeconfig` in namespace `eggs2` is invalid: cluster `eggs2` certificate authority expired at",
+		},
+		{
+			name:          "case 5: certificate expires within warning period",
+			secret:        newTestKubeConfigSecret(t, "eggs2-kubeconfig", "eggs2", "eggs2-kubeconfig", time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)),
+			warningPeriod: 100 * 365 * 24 * time.Hour,
+			expectedWarnings: []string{
+				"kubeconfig secret `eggs2-kubeconfig` in namespace `eggs2` cluster `eggs2` certificate authority expires at 2100-01-01T00:00:00Z",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kiam",
+					Namespace: "eggs2",
+				},
+				Spec: v1alpha1.AppSpec{
+					KubeConfig: v1alpha1.AppSpecKubeConfig{
+						Context: v1alpha1.AppSpecKubeConfigContext{
+							Name: "eggs2-kubeconfig",
+						},
+						Secret: v1alpha1.AppSpecKubeConfigSecret{
+							Name:      "eggs2-kubeconfig",
+							Namespace: "eggs2",
+						},
+					},
+				},
+			}
+
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(),
+				K8sClient: clientgofake.NewSimpleClientset(tc.secret),
+				Logger:    microloggertest.New(),
+
+				CertificateExpiryWarningPeriod: tc.warningPeriod,
+				EnabledRules:                   []string{RuleKubeConfig},
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			warnings, err := r.ValidateAppWithWarnings(ctx, obj)
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil && tc.expectedErr != "" {
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+			}
+
+			if !reflect.DeepEqual(warnings, tc.expectedWarnings) {
+				t.Fatalf("warnings == %#v, want %#v", warnings, tc.expectedWarnings)
+			}
+		})
+	}
+}
+
+// newTestKubeConfigSecret returns a kubeconfig secret with a single context
+// using a token user and a self-signed certificate authority.
+func newTestKubeConfigSecret(t *testing.T, name, namespace, contextName string, notAfter time.Time) *corev1.Secret {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kubernetes"},
+		NotBefore:             notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	data := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: %[1]s
+  cluster:
+    server: https://api.%[1]s.k8s.example.com
+    certificate-authority-data: %[3]s
+users:
+- name: %[1]s
+  user:
+    token: token
+contexts:
+- name: %[2]s
+  context:
+    cluster: %[1]s
+    user: %[1]s
+current-context: %[2]s
+`, namespace, contextName, base64.StdEncoding.EncodeToString(ca))
+
+	return &corev1.Secret{
+		Data: map[string][]byte{
+			key.KubeConfigSecretKey: []byte(data),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+}
@@ -0,0 +1,219 @@
+package validation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+const (
+	defaultKubeVersionTimeout = 5 * time.Second
+
+	// kubeVersionCacheTTL is how long discovered Kubernetes versions are
+	// reused for app CRs targeting the same cluster.
+	kubeVersionCacheTTL = time.Minute
+)
+
+// KubeVersionGetter returns the Kubernetes version of the cluster an app is
+// installed in. For in-cluster apps this is the management cluster.
+type KubeVersionGetter interface {
+	GetKubeVersion(ctx context.Context, cr v1alpha1.App) (string, error)
+}
+
+// kubeVersionGetter discovers the Kubernetes version using the discovery API.
+// Remote clusters are reached using the kubeconfig secret of the app CR.
+type kubeVersionGetter struct {
+	k8sClient kubernetes.Interface
+	metrics   *metrics
+	timeout   time.Duration
+
+	// cache holds the discovered versions by target cluster, see
+	// targetCluster.
+	cache    map[string]cachedKubeVersion
+	cacheTTL time.Duration
+	mutex    sync.Mutex
+
+	// getKubeConfigSecret reads the kubeconfig secret the same way as the
+	// other rules so caches and the reference policy apply.
+	getKubeConfigSecret func(ctx context.Context, cr v1alpha1.App) (*corev1.Secret, error)
+}
+
+type cachedKubeVersion struct {
+	version string
+	expires time.Time
+}
+
+func (g *kubeVersionGetter) GetKubeVersion(ctx context.Context, cr v1alpha1.App) (string, error) {
+	if key.InCluster(cr) {
+		if version, ok := g.cachedVersion(cr); ok {
+			return version, nil
+		}
+
+		g.metrics.observeAPICall("version", "get")
+		info, err := g.k8sClient.Discovery().ServerVersion()
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		g.cacheVersion(cr, info.GitVersion)
+		return info.GitVersion, nil
+	}
+
+	// The secret is read before the cache so the reference policy still
+	// applies to cached versions.
+	secret, err := g.getKubeConfigSecret(ctx, cr)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+	if secret == nil {
+		return "", microerror.Maskf(kubeConfigNotFoundError, resourceNotFoundTemplate, "kubeconfig secret", key.KubeConfigSecretName(cr), key.KubeConfigSecretNamespace(cr))
+	}
+
+	if version, ok := g.cachedVersion(cr); ok {
+		return version, nil
+	}
+
+	clientConfig, err := clientcmd.NewClientConfigFromBytes(secret.Data[key.KubeConfigSecretKey])
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	if key.KubeConfigContextName(cr) != "" {
+		rawConfig, err := clientConfig.RawConfig()
+		if err != nil {
+			return "", microerror.Mask(err)
+		}
+
+		clientConfig = clientcmd.NewNonInteractiveClientConfig(rawConfig, key.KubeConfigContextName(cr), &clientcmd.ConfigOverrides{}, nil)
+	}
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	// The discovery client does not take a context. The timeout keeps
+	// unreachable clusters, e.g. during cluster creation, from blocking
+	// validation past the deadline of the admission request.
+	restConfig.Timeout = g.timeout
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < restConfig.Timeout {
+		restConfig.Timeout = time.Until(deadline)
+	}
+	if restConfig.Timeout <= 0 {
+		return "", microerror.Mask(ctx.Err())
+	}
+
+	k8sClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	g.metrics.observeAPICall("version", "get")
+	info, err := k8sClient.Discovery().ServerVersion()
+	if err != nil {
+		return "", microerror.Mask(err)
+	}
+
+	g.cacheVersion(cr, info.GitVersion)
+	return info.GitVersion, nil
+}
+
+// cachedVersion returns the version of the target cluster of the app CR if
+// it was discovered within the cache TTL.
+func (g *kubeVersionGetter) cachedVersion(cr v1alpha1.App) (string, bool) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	cached, ok := g.cache[targetCluster(cr)]
+	if !ok || time.Now().After(cached.expires) {
+		return "", false
+	}
+
+	return cached.version, true
+}
+
+func (g *kubeVersionGetter) cacheVersion(cr v1alpha1.App, version string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.cache == nil {
+		g.cache = map[string]cachedKubeVersion{}
+	}
+
+	g.cache[targetCluster(cr)] = cachedKubeVersion{
+		version: version,
+		expires: time.Now().Add(g.cacheTTL),
+	}
+}
+
+// getKubeConfigSecret returns the kubeconfig secret of the app CR if the
+// reference policy allows reading it. nil is returned if it is not found.
+func (v *Validator) getKubeConfigSecret(ctx context.Context, cr v1alpha1.App) (*corev1.Secret, error) {
+	secret, err := v.getSecret(ctx, key.KubeConfigSecretNamespace(cr), key.KubeConfigSecretName(cr))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	err = v.validateReference(cr, "spec.kubeConfig.secret.namespace", "kubeconfig secret", key.KubeConfigSecretName(cr), key.KubeConfigSecretNamespace(cr), secretObject(secret))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return secret, nil
+}
+
+func (v *Validator) validateKubeVersion(ctx context.Context, cr v1alpha1.App) error {
+	if key.CatalogName(cr) == "" {
+		return nil
+	}
+
+	entry, err := v.getAppCatalogEntry(ctx, cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	if entry == nil {
+		name := key.AppCatalogEntryName(key.CatalogName(cr), key.AppName(cr), key.Version(cr))
+		v.logger.Debugf(ctx, "appcatalogentry %#q not found, skipping kubernetes version validation", name)
+		return nil
+	}
+
+	if key.AppCatalogEntryKubeVersion(*entry) == "" {
+		// no-op
+		return nil
+	}
+
+	constraint, err := semver.NewConstraint(key.AppCatalogEntryKubeVersion(*entry))
+	if err != nil {
+		return resultErrorf(validationError, ReasonKubeVersionInvalidConstraint, "spec.version", key.Version(cr), kubeVersionInvalidConstraintTemplate, key.AppName(cr), key.AppCatalogEntryKubeVersion(*entry), err)
+	}
+
+	kubeVersion, err := v.kubeVersionGetter.GetKubeVersion(ctx, cr)
+	if err != nil {
+		// The target cluster may not be reachable yet, e.g. during cluster
+		// creation. So we skip the check rather than blocking the app.
+		v.logger.Debugf(ctx, "failed to get kubernetes version of target cluster, skipping kubernetes version validation: %s", err)
+		return nil
+	}
+
+	version, err := semver.NewVersion(kubeVersion)
+	if err != nil {
+		v.logger.Debugf(ctx, "failed to parse kubernetes version %#q of target cluster, skipping kubernetes version validation: %s", kubeVersion, err)
+		return nil
+	}
+
+	if !constraint.Check(version) {
+		return resultErrorf(validationError, ReasonKubeVersionIncompatible, "spec.version", key.Version(cr), kubeVersionIncompatibleTemplate, key.AppName(cr), key.Version(cr), key.AppCatalogEntryKubeVersion(*entry), kubeVersion)
+	}
+
+	return nil
+}
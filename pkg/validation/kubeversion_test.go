@@ -0,0 +1,421 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+type fakeKubeVersionGetter struct {
+	version string
+	calls   int
+}
+
+func (g *fakeKubeVersionGetter) GetKubeVersion(ctx context.Context, cr v1alpha1.App) (string, error) {
+	g.calls++
+	return g.version, nil
+}
+
+func Test_ValidateKubeVersion(t *testing.T) {
+	ctx := context.Background()
+
+	obj := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kiam",
+			Namespace: "eggs2",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "giantswarm",
+			Name:      "kiam",
+			Namespace: "kube-system",
+			Version:   "1.4.0",
+		},
+	}
+
+	tests := []struct {
+		name        string
+		kubeVersion string
+		constraint  string
+		expectedErr string
+	}{
+		{
+			name:        "case 0: no constraint",
+			kubeVersion: "v1.18.9",
+		},
+		{
+			name:        "case 1: compatible version",
+			kubeVersion: "v1.20.4",
+			constraint:  ">=1.19.0-0",
+		},
+		{
+			name:        "case 2: compatible provider version with pre-release",
+			kubeVersion: "v1.20.7-eks-d88609",
+			constraint:  ">=1.19.0-0",
+		},
+		{
+			name:        "case 3: cluster too old",
+			kubeVersion: "v1.18.9",
+			constraint:  ">=1.19.0-0",
+			expectedErr: "validation error: app `kiam` version `1.4.0` requires kubernetes version `>=1.19.0-0` but target cluster has `v1.18.9`",
+		},
+		{
+			name:        "case 4: invalid constraint",
+			kubeVersion: "v1.18.9",
+			constraint:  "one point nineteen",
+			expectedErr: "validation error: app `kiam` declares invalid kubernetes version constraint `one point nineteen`",
+		},
+		{
+			name:        "case 5: unparseable cluster version is skipped",
+			kubeVersion: "v0.0.0-master+$Format:%h$",
+			constraint:  ">=1.19.0-0",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			entry := &v1alpha1.AppCatalogEntry{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "giantswarm-kiam-1.4.0",
+					Namespace: metav1.NamespaceDefault,
+				},
+			}
+			if tc.constraint != "" {
+				entry.Annotations = map[string]string{
+					key.AppCatalogEntryKubeVersionAnnotation: tc.constraint,
+				}
+			}
+
+			c := Config{
+				G8sClient: fake.NewSimpleClientset([]runtime.Object{entry}...),
+				K8sClient: clientgofake.NewSimpleClientset(),
+				Logger:    microloggertest.New(),
+
+				KubeVersionGetter: &fakeKubeVersionGetter{
+					version: tc.kubeVersion,
+				},
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			err = r.validateKubeVersion(ctx, obj)
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil && tc.expectedErr != "" {
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+			}
+		})
+	}
+}
+
+func Test_KubeVersionGetter_RemoteCluster(t *testing.T) {
+	ctx := context.Background()
+
+	// The server never answers like an unreachable cluster.
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	kubeConfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: eggs2
+  cluster:
+    server: %s
+users:
+- name: eggs2
+  user:
+    token: token
+contexts:
+- name: eggs2
+  context:
+    cluster: eggs2
+    user: eggs2
+current-context: eggs2
+`, server.URL)
+
+	tests := []struct {
+		name            string
+		secretNamespace string
+		referencePolicy ReferencePolicy
+		expectedErr     string
+	}{
+		{
+			name:            "case 0: unreachable cluster times out",
+			secretNamespace: "eggs2",
+			expectedErr:     "Client.Timeout exceeded",
+		},
+		{
+			name:            "case 1: kubeconfig secret denied by reference policy",
+			secretNamespace: "org-other",
+			referencePolicy: ReferencePolicySameNamespace,
+			expectedErr:     "validation error: app `kiam` in namespace `eggs2` must not reference kubeconfig secret `eggs2-kubeconfig` in namespace `org-other`",
+		},
+		{
+			name:            "case 2: kubeconfig secret not found",
+			secretNamespace: "eggs3",
+			expectedErr:     "kube config not found error: kubeconfig secret `eggs2-kubeconfig` in namespace `eggs3` not found",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kiam",
+					Namespace: "eggs2",
+				},
+				Spec: v1alpha1.AppSpec{
+					Name:      "kiam",
+					Namespace: "kube-system",
+					KubeConfig: v1alpha1.AppSpecKubeConfig{
+						Secret: v1alpha1.AppSpecKubeConfigSecret{
+							Name:      "eggs2-kubeconfig",
+							Namespace: tc.secretNamespace,
+						},
+					},
+				},
+			}
+
+			secrets := []runtime.Object{
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "eggs2-kubeconfig",
+						Namespace: "eggs2",
+					},
+					Data: map[string][]byte{
+						key.KubeConfigSecretKey: []byte(kubeConfig),
+					},
+				},
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "eggs2-kubeconfig",
+						Namespace: "org-other",
+					},
+					Data: map[string][]byte{
+						key.KubeConfigSecretKey: []byte(kubeConfig),
+					},
+				},
+			}
+
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(),
+				K8sClient: clientgofake.NewSimpleClientset(secrets...),
+				Logger:    microloggertest.New(),
+
+				KubeVersionTimeout: 100 * time.Millisecond,
+				ReferencePolicy:    tc.referencePolicy,
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			start := time.Now()
+			_, err = r.kubeVersionGetter.GetKubeVersion(ctx, obj)
+			if err == nil {
+				t.Fatalf("error == nil, want non-nil")
+			}
+			if !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+			}
+			if time.Since(start) > 5*time.Second {
+				t.Fatalf("GetKubeVersion took %s, want timeout", time.Since(start))
+			}
+		})
+	}
+}
+
+func Test_KubeVersionGetter_Cache(t *testing.T) {
+	ctx := context.Background()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"gitVersion": "v1.20.4"}`)
+	}))
+	defer server.Close()
+
+	kubeConfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: eggs2
+  cluster:
+    server: %s
+users:
+- name: eggs2
+  user:
+    token: token
+contexts:
+- name: eggs2
+  context:
+    cluster: eggs2
+    user: eggs2
+current-context: eggs2
+`, server.URL)
+
+	newApp := func(name string) v1alpha1.App {
+		return v1alpha1.App{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "eggs2",
+			},
+			Spec: v1alpha1.AppSpec{
+				Name:      name,
+				Namespace: "kube-system",
+				KubeConfig: v1alpha1.AppSpecKubeConfig{
+					Secret: v1alpha1.AppSpecKubeConfigSecret{
+						Name:      "eggs2-kubeconfig",
+						Namespace: "eggs2",
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		cacheTTL         time.Duration
+		expectedRequests int32
+	}{
+		{
+			name:             "case 0: version of target cluster is reused",
+			cacheTTL:         time.Minute,
+			expectedRequests: 1,
+		},
+		{
+			name:             "case 1: expired version is discovered again",
+			cacheTTL:         -time.Second,
+			expectedRequests: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			atomic.StoreInt32(&requests, 0)
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "eggs2-kubeconfig",
+					Namespace: "eggs2",
+				},
+				Data: map[string][]byte{
+					key.KubeConfigSecretKey: []byte(kubeConfig),
+				},
+			}
+
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(),
+				K8sClient: clientgofake.NewSimpleClientset(secret),
+				Logger:    microloggertest.New(),
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+			r.kubeVersionGetter.(*kubeVersionGetter).cacheTTL = tc.cacheTTL
+
+			for _, obj := range []v1alpha1.App{newApp("kiam"), newApp("cert-manager")} {
+				version, err := r.kubeVersionGetter.GetKubeVersion(ctx, obj)
+				if err != nil {
+					t.Fatalf("error == %#v, want nil", err)
+				}
+				if version != "v1.20.4" {
+					t.Fatalf("version == %#q, want %#q", version, "v1.20.4")
+				}
+			}
+
+			if n := atomic.LoadInt32(&requests); n != tc.expectedRequests {
+				t.Fatalf("requests == %d, want %d", n, tc.expectedRequests)
+			}
+		})
+	}
+}
+
+func Test_ValidateKubeVersion_RunsLast(t *testing.T) {
+	ctx := context.Background()
+
+	// The name is too long so the name rule rejects the app CR.
+	obj := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      strings.Repeat("kiam", 20),
+			Namespace: "eggs2",
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "giantswarm",
+			Name:      "kiam",
+			Namespace: "kube-system",
+			Version:   "1.4.0",
+		},
+	}
+
+	entry := &v1alpha1.AppCatalogEntry{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "giantswarm-kiam-1.4.0",
+			Namespace: metav1.NamespaceDefault,
+			Annotations: map[string]string{
+				key.AppCatalogEntryKubeVersionAnnotation: ">=1.19.0-0",
+			},
+		},
+	}
+
+	getter := &fakeKubeVersionGetter{
+		version: "v1.20.4",
+	}
+
+	c := Config{
+		G8sClient: fake.NewSimpleClientset(entry),
+		K8sClient: clientgofake.NewSimpleClientset(),
+		Logger:    microloggertest.New(),
+
+		EnabledRules:      []string{RuleKubeVersion, RuleName},
+		KubeVersionGetter: getter,
+
+		Provider: "aws",
+	}
+	r, err := NewValidator(c)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	_, err = r.ValidateApp(ctx, obj)
+	if !IsValidationError(err) {
+		t.Fatalf("error == %#v, want validation error", err)
+	}
+	if getter.calls != 0 {
+		t.Fatalf("calls == %d, want 0", getter.calls)
+	}
+}
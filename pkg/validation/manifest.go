@@ -0,0 +1,249 @@
+package validation
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/microerror"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+var (
+	manifestExtensions = []string{".json", ".yaml", ".yml"}
+	manifestScheme     = newManifestScheme()
+)
+
+// Manifests holds the app CRs and the resources they reference loaded from
+// manifest files. It backs a validator in offline mode, e.g. to validate a
+// GitOps repository in CI without a cluster.
+type Manifests struct {
+	apps       []v1alpha1.App
+	files      map[string]string
+	g8sObjects []runtime.Object
+	k8sObjects []runtime.Object
+}
+
+// ManifestResult is the outcome of validating one app CR loaded from a
+// manifest file.
+type ManifestResult struct {
+	// File is the path of the file the app CR was loaded from.
+	File string
+	// App is the validated app CR.
+	App v1alpha1.App
+	// Warnings are the non-fatal findings.
+	Warnings []string
+	// Err is the error rejecting the app CR or nil if it is valid.
+	Err error
+}
+
+// LoadManifests loads the apps, appcatalogentries, catalogs, configmaps and
+// secrets from the given files. Directories are walked recursively and
+// their .json, .yaml and .yml files loaded. Files may contain multiple YAML
+// documents. Resources of other kinds are ignored. Resources without
+// namespace are put in the default namespace.
+func LoadManifests(paths ...string) (*Manifests, error) {
+	m := &Manifests{
+		files: map[string]string{},
+	}
+
+	for _, p := range paths {
+		err := filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			if info.IsDir() || (path != p && !containsString(manifestExtensions, strings.ToLower(filepath.Ext(path)))) {
+				return nil
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				return microerror.Mask(err)
+			}
+			defer f.Close()
+
+			return m.load(path, f)
+		})
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	return m, nil
+}
+
+// Apps returns the loaded app CRs sorted by file, namespace and name.
+func (m *Manifests) Apps() []v1alpha1.App {
+	apps := make([]v1alpha1.App, len(m.apps))
+	copy(apps, m.apps)
+
+	sort.SliceStable(apps, func(i, j int) bool {
+		fi, fj := m.File(&apps[i]), m.File(&apps[j])
+		if fi != fj {
+			return fi < fj
+		}
+		return appKey(apps[i].Namespace, apps[i].Name) < appKey(apps[j].Namespace, apps[j].Name)
+	})
+
+	return apps
+}
+
+// Clients returns clients backed by an in-memory store of the loaded
+// resources. They can be used as Config.G8sClient and Config.K8sClient.
+func (m *Manifests) Clients() (versioned.Interface, kubernetes.Interface) {
+	return fake.NewSimpleClientset(m.g8sObjects...), clientgofake.NewSimpleClientset(m.k8sObjects...)
+}
+
+// File returns the path of the file the resource was loaded from or an
+// empty string if it was not loaded from a manifest.
+func (m *Manifests) File(obj runtime.Object) string {
+	return m.files[manifestKey(obj)]
+}
+
+func (m *Manifests) load(path string, r io.Reader) error {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	decoder := serializer.NewCodecFactory(manifestScheme).UniversalDeserializer()
+
+	for i := 0; ; i++ {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return microerror.Maskf(invalidManifestError, "file %#q document %d: %s", path, i, err)
+		}
+
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		obj, _, err := decoder.Decode(doc, nil, nil)
+		if runtime.IsNotRegisteredError(err) {
+			// Manifests of other kinds are not needed for validation.
+			continue
+		} else if err != nil {
+			return microerror.Maskf(invalidManifestError, "file %#q document %d: %s", path, i, err)
+		}
+
+		err = m.add(path, obj)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Manifests) add(path string, obj runtime.Object) error {
+	accessor, ok := obj.(metav1.Object)
+	if !ok {
+		return nil
+	}
+	if accessor.GetNamespace() == "" {
+		accessor.SetNamespace(metav1.NamespaceDefault)
+	}
+
+	switch o := obj.(type) {
+	case *v1alpha1.App:
+		m.apps = append(m.apps, *o)
+		m.g8sObjects = append(m.g8sObjects, o)
+	case *v1alpha1.AppCatalogEntry, *v1alpha1.Catalog:
+		m.g8sObjects = append(m.g8sObjects, o)
+	case *corev1.ConfigMap:
+		m.k8sObjects = append(m.k8sObjects, o)
+	case *corev1.Secret:
+		// The API server merges stringData into data. This is done here
+		// as the resources are not sent to an API server.
+		for k, v := range o.StringData {
+			if o.Data == nil {
+				o.Data = map[string][]byte{}
+			}
+			o.Data[k] = []byte(v)
+		}
+		o.StringData = nil
+		m.k8sObjects = append(m.k8sObjects, o)
+	default:
+		return nil
+	}
+
+	k := manifestKey(obj)
+	if existing, ok := m.files[k]; ok {
+		return microerror.Maskf(invalidManifestError, "file %#q contains %s which is already defined in file %#q", path, k, existing)
+	}
+	m.files[k] = path
+
+	return nil
+}
+
+// ValidateManifests validates all app CRs of the manifests. The validator
+// must use the clients returned by Manifests.Clients. The results are in the
+// order of Manifests.Apps.
+func (v *Validator) ValidateManifests(ctx context.Context, m *Manifests) []ManifestResult {
+	var results []ManifestResult
+
+	for _, app := range m.Apps() {
+		warnings, err := v.ValidateAppWithWarnings(ctx, app)
+
+		results = append(results, ManifestResult{
+			File:     m.File(&app),
+			App:      app,
+			Warnings: warnings,
+			Err:      err,
+		})
+	}
+
+	return results
+}
+
+// manifestKey identifies a resource by kind, namespace and name.
+func manifestKey(obj runtime.Object) string {
+	accessor, ok := obj.(metav1.Object)
+	if !ok {
+		return ""
+	}
+
+	var kind string
+	switch obj.(type) {
+	case *v1alpha1.App:
+		kind = "App"
+	case *v1alpha1.AppCatalogEntry:
+		kind = "AppCatalogEntry"
+	case *v1alpha1.Catalog:
+		kind = "Catalog"
+	case *corev1.ConfigMap:
+		kind = "ConfigMap"
+	case *corev1.Secret:
+		kind = "Secret"
+	}
+
+	return fmt.Sprintf("%s %s", kind, appKey(accessor.GetNamespace(), accessor.GetName()))
+}
+
+func newManifestScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+
+	err := v1alpha1.AddToScheme(s)
+	if err != nil {
+		panic(err)
+	}
+	err = corev1.AddToScheme(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return s
+}
@@ -0,0 +1,210 @@
+package validation
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	testManifestApps = `apiVersion: application.giantswarm.io/v1alpha1
+kind: App
+metadata:
+  name: kiam
+  namespace: eggs2
+  labels:
+    app-operator.giantswarm.io/version: 0.0.0
+spec:
+  catalog: giantswarm
+  name: kiam
+  namespace: kube-system
+  version: 1.4.0
+  kubeConfig:
+    inCluster: true
+  userConfig:
+    configMap:
+      name: kiam-user-values
+      namespace: eggs2
+---
+apiVersion: application.giantswarm.io/v1alpha1
+kind: App
+metadata:
+  name: cert-manager
+  namespace: eggs2
+  labels:
+    app-operator.giantswarm.io/version: 0.0.0
+spec:
+  catalog: giantswarm
+  name: cert-manager
+  namespace: kube-system
+  version: 2.0.0
+  kubeConfig:
+    inCluster: true
+  userConfig:
+    secret:
+      name: cert-manager-user-secrets
+      namespace: eggs2
+`
+	testManifestCatalog = `apiVersion: application.giantswarm.io/v1alpha1
+kind: Catalog
+metadata:
+  name: giantswarm
+  namespace: default
+spec:
+  title: Giant Swarm
+  storage:
+    type: helm
+    URL: https://giantswarm.github.io/giantswarm-catalog/
+`
+	testManifestConfig = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kiam-user-values
+  namespace: eggs2
+data:
+  values: |
+    replicas: 2
+---
+apiVersion: kustomize.toolkit.fluxcd.io/v1beta1
+kind: Kustomization
+metadata:
+  name: ignored
+`
+)
+
+func Test_ValidateManifests(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	writeTestManifest(t, dir, "apps/apps.yaml", testManifestApps)
+	writeTestManifest(t, dir, "catalog.yml", testManifestCatalog)
+	writeTestManifest(t, dir, "apps/config.yaml", testManifestConfig)
+	writeTestManifest(t, dir, "README.md", "not a manifest")
+
+	m, err := LoadManifests(dir)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	g8sClient, k8sClient := m.Clients()
+
+	c := Config{
+		G8sClient: g8sClient,
+		K8sClient: k8sClient,
+		Logger:    microloggertest.New(),
+
+		KubeVersionGetter: &fakeKubeVersionGetter{},
+
+		Provider: "aws",
+	}
+	r, err := NewValidator(c)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	results := r.ValidateManifests(ctx, m)
+	if len(results) != 2 {
+		t.Fatalf("len(results) == %d, want 2", len(results))
+	}
+
+	appsFile := filepath.Join(dir, "apps/apps.yaml")
+
+	// Results are sorted by file and app.
+	if results[0].App.Name != "cert-manager" || results[0].File != appsFile {
+		t.Fatalf("results[0] == %#q in %#q, want %#q in %#q", results[0].App.Name, results[0].File, "cert-manager", appsFile)
+	}
+	expectedErr := "validation error: secret `cert-manager-user-secrets` in namespace `eggs2` not found"
+	if results[0].Err == nil || !strings.Contains(results[0].Err.Error(), expectedErr) {
+		t.Fatalf("results[0].Err == %v, want %#q", results[0].Err, expectedErr)
+	}
+	expectedWarnings := []string{
+		"app `cert-manager` version `2.0.0` not found in catalog `giantswarm`, metadata restrictions are not validated",
+	}
+	if !reflect.DeepEqual(results[0].Warnings, expectedWarnings) {
+		t.Fatalf("results[0].Warnings == %#v, want %#v", results[0].Warnings, expectedWarnings)
+	}
+
+	if results[1].App.Name != "kiam" || results[1].File != appsFile {
+		t.Fatalf("results[1] == %#q in %#q, want %#q in %#q", results[1].App.Name, results[1].File, "kiam", appsFile)
+	}
+	if results[1].Err != nil {
+		t.Fatalf("results[1].Err == %#v, want nil", results[1].Err)
+	}
+
+	catalog, err := g8sClient.ApplicationV1alpha1().Catalogs("default").Get(ctx, "giantswarm", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	if m.File(catalog) != filepath.Join(dir, "catalog.yml") {
+		t.Fatalf("file == %#q, want %#q", m.File(catalog), filepath.Join(dir, "catalog.yml"))
+	}
+}
+
+func Test_LoadManifests_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		files       map[string]string
+		expectedErr string
+	}{
+		{
+			name: "case 0: duplicate resource",
+			files: map[string]string{
+				"a.yaml": testManifestCatalog,
+				"b.yaml": testManifestCatalog,
+			},
+			expectedErr: "b.yaml` contains Catalog default/giantswarm which is already defined in file",
+		},
+		{
+			name: "case 1: broken YAML",
+			files: map[string]string{
+				"a.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata: [",
+			},
+			expectedErr: "a.yaml` document 0:",
+		},
+		{
+			name: "case 2: missing kind",
+			files: map[string]string{
+				"a.yaml": testManifestCatalog + "---\napiVersion: v1\nmetadata:\n  name: foo\n",
+			},
+			expectedErr: "a.yaml` document 1:",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tc.files {
+				writeTestManifest(t, dir, name, content)
+			}
+
+			_, err := LoadManifests(dir)
+			if !IsInvalidManifest(err) {
+				t.Fatalf("error == %#v, want invalid manifest error", err)
+			}
+			if !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+			}
+		})
+	}
+}
+
+func writeTestManifest(t *testing.T, dir, name, content string) {
+	path := filepath.Join(dir, name)
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	err = ioutil.WriteFile(path, []byte(content), 0600)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+}
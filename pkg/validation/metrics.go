@@ -0,0 +1,138 @@
+package validation
+
+import (
+	"time"
+
+	"github.com/giantswarm/microerror"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	metricsNamespace = "app"
+	metricsSubsystem = "validation"
+
+	labelIndex    = "index"
+	labelReason   = "reason"
+	labelResource = "resource"
+	labelResult   = "result"
+	labelRule     = "rule"
+	labelVerb     = "verb"
+
+	// resultAccepted, resultRejected and resultFailed are the values of the
+	// result label. Failed validations could not be completed, e.g. because
+	// of an API error.
+	resultAccepted = "accepted"
+	resultFailed   = "failed"
+	resultRejected = "rejected"
+
+	// reasonUnknown is used for validation errors without a result, e.g.
+	// returned by custom rules.
+	reasonUnknown = "Unknown"
+)
+
+// metrics holds the collectors of a validator. They are always created but
+// only registered when Config.Registerer is set.
+type metrics struct {
+	apiCalls         *prometheus.CounterVec
+	clusterWideLists *prometheus.CounterVec
+	ruleDuration     *prometheus.HistogramVec
+	validations      *prometheus.CounterVec
+}
+
+func newMetrics(registerer prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		apiCalls: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Subsystem: metricsSubsystem,
+				Name:      "api_calls_total",
+				Help:      "Number of Kubernetes API calls made while validating app CRs.",
+			},
+			[]string{labelResource, labelVerb},
+		),
+		clusterWideLists: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Subsystem: metricsSubsystem,
+				Name:      "cluster_wide_app_lists_total",
+				Help:      "Number of app CR lists across all namespaces made because no synced app informer is configured.",
+			},
+			[]string{labelIndex},
+		),
+		ruleDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: metricsNamespace,
+				Subsystem: metricsSubsystem,
+				Name:      "rule_duration_seconds",
+				Help:      "Duration of validation rules in seconds.",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{labelRule},
+		),
+		validations: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Subsystem: metricsSubsystem,
+				Name:      "validations_total",
+				Help:      "Number of app CR validations by result and rejection reason.",
+			},
+			[]string{labelResult, labelReason},
+		),
+	}
+
+	if registerer != nil {
+		for _, c := range []prometheus.Collector{m.apiCalls, m.clusterWideLists, m.ruleDuration, m.validations} {
+			err := registerer.Register(c)
+			if err != nil {
+				return nil, microerror.Mask(err)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// observeAPICall counts a call to the Kubernetes API.
+func (m *metrics) observeAPICall(resource, verb string) {
+	m.apiCalls.WithLabelValues(resource, verb).Inc()
+}
+
+// observeClusterWideList counts a list of app CRs across all namespaces
+// made instead of reading the index of the app informer.
+func (m *metrics) observeClusterWideList(index string) {
+	m.clusterWideLists.WithLabelValues(index).Inc()
+}
+
+// observeRule records how long the rule took since start.
+func (m *metrics) observeRule(rule string, start time.Time) {
+	m.ruleDuration.WithLabelValues(rule).Observe(time.Since(start).Seconds())
+}
+
+// observeValidation counts the outcome of a validation returning err.
+func (m *metrics) observeValidation(err error) {
+	status := validationStatus(err)
+
+	var reason string
+	if status == resultRejected {
+		reason = reasonUnknown
+		if result, ok := ResultFromError(err); ok {
+			reason = string(result.Reason)
+		}
+	}
+
+	m.validations.WithLabelValues(status, reason).Inc()
+}
+
+// validationStatus returns whether the app CR was accepted, rejected because
+// it is invalid or whether validation failed, e.g. because the Kubernetes
+// API could not be reached.
+func validationStatus(err error) string {
+	switch {
+	case err == nil:
+		return resultAccepted
+	case IsValidationError(err) || IsAppConfigMapNotFound(err) || IsKubeConfigNotFound(err):
+		return resultRejected
+	default:
+		return resultFailed
+	}
+}
@@ -0,0 +1,120 @@
+package validation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_Metrics(t *testing.T) {
+	ctx := context.Background()
+
+	registry := prometheus.NewRegistry()
+
+	c := Config{
+		G8sClient: fake.NewSimpleClientset(newTestCatalog("giantswarm", "giantswarm")),
+		K8sClient: clientgofake.NewSimpleClientset(),
+		Logger:    microloggertest.New(),
+
+		EnabledRules: []string{RuleCatalog, RuleName},
+		Registerer:   registry,
+
+		Provider: "aws",
+	}
+	r, err := NewValidator(c)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	apps := []v1alpha1.App{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kiam",
+				Namespace: "eggs2",
+			},
+			Spec: v1alpha1.AppSpec{
+				Catalog:   "giantswarm",
+				Name:      "kiam",
+				Namespace: "kube-system",
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kiam",
+				Namespace: "eggs2",
+			},
+			Spec: v1alpha1.AppSpec{
+				Catalog:   "missing",
+				Name:      "kiam",
+				Namespace: "kube-system",
+			},
+		},
+	}
+
+	for _, app := range apps {
+		_, _ = r.ValidateApp(ctx, app)
+	}
+
+	tests := []struct {
+		name      string
+		collector prometheus.Collector
+		expected  float64
+	}{
+		{
+			name:      "case 0: accepted validations",
+			collector: r.metrics.validations.WithLabelValues(resultAccepted, ""),
+			expected:  1,
+		},
+		{
+			name:      "case 1: rejected validations by reason",
+			collector: r.metrics.validations.WithLabelValues(resultRejected, string(ReasonCatalogNotFound)),
+			expected:  1,
+		},
+		{
+			name:      "case 2: catalog gets in default and giantswarm namespaces",
+			collector: r.metrics.apiCalls.WithLabelValues("catalogs", "get"),
+			expected:  4,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			value := testutil.ToFloat64(tc.collector)
+			if value != tc.expected {
+				t.Fatalf("value == %v, want %v", value, tc.expected)
+			}
+		})
+	}
+
+	t.Run("registered", func(t *testing.T) {
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatalf("error == %#v, want nil", err)
+		}
+
+		names := map[string]bool{}
+		for _, f := range families {
+			names[f.GetName()] = true
+		}
+
+		for _, name := range []string{"app_validation_api_calls_total", "app_validation_rule_duration_seconds", "app_validation_validations_total"} {
+			if !names[name] {
+				t.Fatalf("metric %#q not registered", name)
+			}
+		}
+	})
+
+	t.Run("registered twice", func(t *testing.T) {
+		_, err := NewValidator(c)
+		if err == nil {
+			t.Fatalf("error == nil, want non-nil")
+		}
+	})
+}
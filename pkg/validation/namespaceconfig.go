@@ -0,0 +1,107 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+const (
+	// namespaceAnnotationsMaxSize is the maximum total size of annotations
+	// accepted by the Kubernetes API.
+	namespaceAnnotationsMaxSize = 256 * (1 << 10)
+)
+
+// defaultDeniedNamespaceConfigPrefixes are the key prefixes app CRs must not
+// set on their target namespace. They protect namespace security policy
+// such as pod security admission labels.
+var defaultDeniedNamespaceConfigPrefixes = []string{
+	"giantswarm.io/",
+	"k8s.io/",
+	"kubernetes.io/",
+	"pod-security.kubernetes.io/",
+}
+
+// validateNamespaceConfigKeys checks the syntax of the namespace labels and
+// annotations of the app CR and rejects denied keys. Keys are checked in
+// order so the same error is returned for the same app CR.
+func (v *Validator) validateNamespaceConfigKeys(cr v1alpha1.App) error {
+	labels := key.AppNamespaceLabels(cr)
+	for _, k := range sortedKeys(labels) {
+		field := fmt.Sprintf("spec.namespaceConfig.labels[%s]", k)
+
+		if errs := utilvalidation.IsQualifiedName(k); len(errs) > 0 {
+			return resultErrorf(validationError, ReasonNamespaceConfigInvalid, field, k, namespaceConfigKeyInvalidTemplate,
+				key.AppName(cr), "label", k, strings.Join(errs, ", "))
+		}
+		if errs := utilvalidation.IsValidLabelValue(labels[k]); len(errs) > 0 {
+			return resultErrorf(validationError, ReasonNamespaceConfigInvalid, field, labels[k], namespaceConfigValueInvalidTemplate,
+				key.AppName(cr), "label", k, labels[k], strings.Join(errs, ", "))
+		}
+		if prefix, ok := v.deniedNamespaceConfigPrefix(k); ok {
+			return resultErrorf(validationError, ReasonNamespaceConfigDenied, field, k, namespaceConfigKeyDeniedTemplate,
+				key.AppName(cr), "label", k, prefix)
+		}
+	}
+
+	annotations := key.AppNamespaceAnnotations(cr)
+	var size int
+	for _, k := range sortedKeys(annotations) {
+		field := fmt.Sprintf("spec.namespaceConfig.annotations[%s]", k)
+
+		if errs := utilvalidation.IsQualifiedName(strings.ToLower(k)); len(errs) > 0 {
+			return resultErrorf(validationError, ReasonNamespaceConfigInvalid, field, k, namespaceConfigKeyInvalidTemplate,
+				key.AppName(cr), "annotation", k, strings.Join(errs, ", "))
+		}
+		if prefix, ok := v.deniedNamespaceConfigPrefix(k); ok {
+			return resultErrorf(validationError, ReasonNamespaceConfigDenied, field, k, namespaceConfigKeyDeniedTemplate,
+				key.AppName(cr), "annotation", k, prefix)
+		}
+
+		size += len(k) + len(annotations[k])
+	}
+	if size > namespaceAnnotationsMaxSize {
+		return resultErrorf(validationError, ReasonNamespaceConfigInvalid, "spec.namespaceConfig.annotations", "", "app %#q namespace annotations are %d bytes and exceed max size of %d bytes",
+			key.AppName(cr), size, namespaceAnnotationsMaxSize)
+	}
+
+	return nil
+}
+
+// deniedNamespaceConfigPrefix returns the denied prefix matching the key.
+// Prefixes ending with a slash deny the domain and all its subdomains, e.g.
+// kubernetes.io/ also denies pod-security.kubernetes.io/enforce.
+func (v *Validator) deniedNamespaceConfigPrefix(k string) (string, bool) {
+	for _, prefix := range v.deniedNamespaceConfigPrefixes {
+		if strings.HasPrefix(k, prefix) {
+			return prefix, true
+		}
+
+		domain := strings.TrimSuffix(prefix, "/")
+		if domain == prefix {
+			continue
+		}
+
+		parts := strings.SplitN(k, "/", 2)
+		if len(parts) == 2 && strings.HasSuffix(parts[0], "."+domain) {
+			return prefix, true
+		}
+	}
+
+	return "", false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,148 @@
+package validation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_ValidateNamespaceConfigKeys(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		annotations    map[string]string
+		labels         map[string]string
+		deniedPrefixes []string
+		expectedErr    string
+	}{
+		{
+			name: "case 0: flawless",
+			annotations: map[string]string{
+				"linkerd.io/inject": "enabled",
+			},
+			labels: map[string]string{
+				"monitoring": "enabled",
+			},
+		},
+		{
+			name: "case 1: invalid label key",
+			labels: map[string]string{
+				"monitoring/enabled/now": "true",
+			},
+			expectedErr: "validation error: app `kiam` namespace label key `monitoring/enabled/now` is invalid: a qualified name must consist of",
+		},
+		{
+			name: "case 2: invalid label value",
+			labels: map[string]string{
+				"monitoring": "enabled and scraped",
+			},
+			expectedErr: "validation error: app `kiam` namespace label `monitoring` has invalid value `enabled and scraped`: a valid label must be",
+		},
+		{
+			name: "case 3: invalid annotation key",
+			annotations: map[string]string{
+				"-linkerd.io/inject": "enabled",
+			},
+			expectedErr: "validation error: app `kiam` namespace annotation key `-linkerd.io/inject` is invalid:",
+		},
+		{
+			name: "case 4: pod security label",
+			labels: map[string]string{
+				"pod-security.kubernetes.io/enforce": "privileged",
+			},
+			expectedErr: "validation error: app `kiam` namespace label `pod-security.kubernetes.io/enforce` is denied by prefix `kubernetes.io/`",
+		},
+		{
+			name: "case 5: giantswarm annotation",
+			annotations: map[string]string{
+				"giantswarm.io/service-type": "managed",
+			},
+			expectedErr: "validation error: app `kiam` namespace annotation `giantswarm.io/service-type` is denied by prefix `giantswarm.io/`",
+		},
+		{
+			name: "case 6: subdomain of denied domain",
+			labels: map[string]string{
+				"policy.giantswarm.io/psp": "restricted",
+			},
+			expectedErr: "validation error: app `kiam` namespace label `policy.giantswarm.io/psp` is denied by prefix `giantswarm.io/`",
+		},
+		{
+			name: "case 7: configured denied prefixes replace defaults",
+			labels: map[string]string{
+				"giantswarm.io/team": "ops",
+				"acme.com/team":      "ops",
+			},
+			deniedPrefixes: []string{"acme.com/"},
+			expectedErr:    "validation error: app `kiam` namespace label `acme.com/team` is denied by prefix `acme.com/`",
+		},
+		{
+			name: "case 8: annotations too large",
+			annotations: map[string]string{
+				"linkerd.io/config": strings.Repeat("a", namespaceAnnotationsMaxSize),
+			},
+			expectedErr: "validation error: app `kiam` namespace annotations are 262161 bytes and exceed max size of 262144 bytes",
+		},
+		{
+			name: "case 9: empty denied prefixes deny nothing",
+			labels: map[string]string{
+				"app.kubernetes.io/part-of": "monitoring",
+				"giantswarm.io/team":        "ops",
+			},
+			deniedPrefixes: []string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kiam",
+					Namespace: "eggs2",
+				},
+				Spec: v1alpha1.AppSpec{
+					Name:      "kiam",
+					Namespace: "kube-system",
+					NamespaceConfig: v1alpha1.AppSpecNamespaceConfig{
+						Annotations: tc.annotations,
+						Labels:      tc.labels,
+					},
+				},
+			}
+
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(),
+				K8sClient: clientgofake.NewSimpleClientset(),
+				Logger:    microloggertest.New(),
+
+				DeniedNamespaceConfigPrefixes: tc.deniedPrefixes,
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			err = r.validateNamespaceConfig(ctx, obj)
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil && tc.expectedErr != "" {
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package validation
+
+import (
+	"context"
+	"io/ioutil"
+	"path"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/microerror"
+	"sigs.k8s.io/yaml"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+// Policy restricts which apps may be installed. It is usually loaded from a
+// YAML document so it can be reviewed in Git.
+//
+//	rules:
+//	- namespaces:
+//	  - org-*
+//	  allow:
+//	  - catalogs:
+//	    - giantswarm
+//	  - catalogs:
+//	    - community
+//	    apps:
+//	    - cert-manager-app
+//	    versions: ">=2.0.0"
+//	    targetNamespaces:
+//	    - cert-manager
+type Policy struct {
+	Rules []PolicyRule `json:"rules"`
+}
+
+// PolicyRule restricts the apps in the app CR namespaces it selects. The
+// namespaces are organization or cluster namespaces. An app CR selected by
+// at least one rule must match an allow entry of one of the selected rules.
+// App CRs not selected by any rule are allowed.
+type PolicyRule struct {
+	// Namespaces are glob patterns matching app CR namespaces.
+	// e.g. org-*
+	Namespaces []string `json:"namespaces"`
+	// Allow lists the apps allowed in the selected namespaces.
+	Allow []PolicyAllow `json:"allow"`
+}
+
+// PolicyAllow matches app CRs. Empty fields match all values.
+type PolicyAllow struct {
+	// Catalogs are the allowed catalog names.
+	Catalogs []string `json:"catalogs,omitempty"`
+	// Apps are the allowed app names.
+	Apps []string `json:"apps,omitempty"`
+	// Versions is a semver constraint for the allowed app versions.
+	// e.g. >=1.0.0 <2.0.0
+	Versions string `json:"versions,omitempty"`
+	// TargetNamespaces are glob patterns matching the namespaces apps are
+	// allowed to be installed in.
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+}
+
+// LoadPolicy reads and parses the policy document at the given path.
+func LoadPolicy(filePath string) (*Policy, error) {
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	policy, err := ParsePolicy(data)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return policy, nil
+}
+
+// ParsePolicy parses a YAML or JSON policy document. Unknown fields, invalid
+// glob patterns and invalid version constraints are rejected.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var policy Policy
+
+	err := yaml.UnmarshalStrict(data, &policy)
+	if err != nil {
+		return nil, microerror.Maskf(invalidPolicyError, "%s", err)
+	}
+
+	err = policy.Validate()
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return &policy, nil
+}
+
+// Validate checks the rules have namespaces, valid glob patterns and valid
+// version constraints. It is called by ParsePolicy and NewValidator so
+// policies built in Go are checked too.
+func (p *Policy) Validate() error {
+	for i, r := range p.Rules {
+		if len(r.Namespaces) == 0 {
+			return microerror.Maskf(invalidPolicyError, "rules[%d].namespaces must not be empty", i)
+		}
+		if p := invalidPattern(r.Namespaces); p != "" {
+			return microerror.Maskf(invalidPolicyError, "rules[%d].namespaces has invalid pattern %#q", i, p)
+		}
+
+		for j, a := range r.Allow {
+			if p := invalidPattern(a.TargetNamespaces); p != "" {
+				return microerror.Maskf(invalidPolicyError, "rules[%d].allow[%d].targetNamespaces has invalid pattern %#q", i, j, p)
+			}
+
+			if a.Versions != "" {
+				_, err := semver.NewConstraint(a.Versions)
+				if err != nil {
+					return microerror.Maskf(invalidPolicyError, "rules[%d].allow[%d].versions: %s", i, j, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Allows returns whether the app CR is allowed by the policy.
+func (p *Policy) Allows(cr v1alpha1.App) bool {
+	var selected bool
+
+	for _, r := range p.Rules {
+		if !matchesPattern(r.Namespaces, cr.Namespace) {
+			continue
+		}
+		selected = true
+
+		for _, a := range r.Allow {
+			if a.matches(cr) {
+				return true
+			}
+		}
+	}
+
+	return !selected
+}
+
+func (a PolicyAllow) matches(cr v1alpha1.App) bool {
+	if len(a.Catalogs) > 0 && !containsString(a.Catalogs, key.CatalogName(cr)) {
+		return false
+	}
+	if len(a.Apps) > 0 && !containsString(a.Apps, key.AppName(cr)) {
+		return false
+	}
+	if len(a.TargetNamespaces) > 0 && !matchesPattern(a.TargetNamespaces, key.AppNamespace(cr)) {
+		return false
+	}
+
+	if a.Versions != "" {
+		// Invalid constraints match nothing so a policy that was not
+		// validated fails closed.
+		constraint, err := semver.NewConstraint(a.Versions)
+		if err != nil {
+			return false
+		}
+		version, err := semver.NewVersion(key.Version(cr))
+		if err != nil {
+			return false
+		}
+		if !constraint.Check(version) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (v *Validator) validatePolicy(ctx context.Context, cr v1alpha1.App) error {
+	if v.policy == nil {
+		return nil
+	}
+
+	if !v.policy.Allows(cr) {
+		return resultErrorf(validationError, ReasonPolicyNotAllowed, "spec.name", key.AppName(cr), policyNotAllowedTemplate, key.AppName(cr), key.Version(cr), key.CatalogName(cr), key.AppNamespace(cr), cr.Namespace)
+	}
+
+	return nil
+}
+
+func matchesPattern(patterns []string, s string) bool {
+	for _, p := range patterns {
+		// Invalid patterns match nothing. They are rejected by Validate.
+		ok, _ := path.Match(p, s)
+		if ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// invalidPattern returns the first malformed glob pattern or an empty string.
+func invalidPattern(patterns []string) string {
+	for _, p := range patterns {
+		_, err := path.Match(p, "")
+		if err != nil {
+			return p
+		}
+	}
+
+	return ""
+}
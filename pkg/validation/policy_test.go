@@ -0,0 +1,212 @@
+package validation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+const testPolicy = `
+rules:
+- namespaces:
+  - org-*
+  allow:
+  - catalogs:
+    - giantswarm
+  - catalogs:
+    - community
+    apps:
+    - cert-manager-app
+    versions: ">=2.0.0"
+    targetNamespaces:
+    - cert-*
+`
+
+func Test_ParsePolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:   "case 0: valid policy",
+			policy: testPolicy,
+		},
+		{
+			name:         "case 1: unknown field",
+			policy:       "rules:\n- namespace: org-*\n",
+			errorMatcher: IsInvalidPolicy,
+		},
+		{
+			name:         "case 2: invalid pattern",
+			policy:       "rules:\n- namespaces:\n  - org-[\n",
+			errorMatcher: IsInvalidPolicy,
+		},
+		{
+			name:         "case 3: invalid version constraint",
+			policy:       "rules:\n- namespaces:\n  - org-*\n  allow:\n  - versions: two\n",
+			errorMatcher: IsInvalidPolicy,
+		},
+		{
+			name:         "case 4: rule without namespaces",
+			policy:       "rules:\n- allow:\n  - catalogs:\n    - giantswarm\n",
+			errorMatcher: IsInvalidPolicy,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(tc.policy))
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+		})
+	}
+}
+
+func Test_ValidatePolicy(t *testing.T) {
+	ctx := context.Background()
+
+	policy, err := ParsePolicy([]byte(testPolicy))
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	tests := []struct {
+		name        string
+		obj         v1alpha1.App
+		expectedErr string
+	}{
+		{
+			name: "case 0: namespace not selected by policy",
+			obj:  newTestPolicyApp("eggs2", "community", "kiam", "1.4.0", "kube-system"),
+		},
+		{
+			name: "case 1: catalog allowed",
+			obj:  newTestPolicyApp("org-acme", "giantswarm", "kiam", "1.4.0", "kube-system"),
+		},
+		{
+			name: "case 2: community app allowed",
+			obj:  newTestPolicyApp("org-acme", "community", "cert-manager-app", "2.4.0", "cert-manager"),
+		},
+		{
+			name:        "case 3: community app not allowed",
+			obj:         newTestPolicyApp("org-acme", "community", "kiam", "1.4.0", "kube-system"),
+			expectedErr: "validation error: app `kiam` version `1.4.0` from catalog `community` in target namespace `kube-system` is not allowed by policy for namespace `org-acme`",
+		},
+		{
+			name:        "case 4: community app version not allowed",
+			obj:         newTestPolicyApp("org-acme", "community", "cert-manager-app", "1.9.0", "cert-manager"),
+			expectedErr: "validation error: app `cert-manager-app` version `1.9.0` from catalog `community` in target namespace `cert-manager` is not allowed by policy for namespace `org-acme`",
+		},
+		{
+			name:        "case 5: community app target namespace not allowed",
+			obj:         newTestPolicyApp("org-acme", "community", "cert-manager-app", "2.4.0", "kube-system"),
+			expectedErr: "validation error: app `cert-manager-app` version `2.4.0` from catalog `community` in target namespace `kube-system` is not allowed by policy for namespace `org-acme`",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(),
+				K8sClient: clientgofake.NewSimpleClientset(),
+				Logger:    microloggertest.New(),
+
+				Policy: policy,
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			err = r.validatePolicy(ctx, tc.obj)
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil && tc.expectedErr != "" {
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+			}
+		})
+	}
+}
+
+func Test_Policy_BuiltInGo(t *testing.T) {
+	policy := &Policy{
+		Rules: []PolicyRule{
+			{
+				Namespaces: []string{"org-*"},
+				Allow: []PolicyAllow{
+					{
+						Catalogs: []string{"community"},
+						Versions: ">=2.0.0",
+					},
+				},
+			},
+		},
+	}
+
+	if policy.Allows(newTestPolicyApp("org-acme", "community", "cert-manager-app", "1.0.0", "cert-manager")) {
+		t.Fatalf("Allows == true, want false for version below constraint")
+	}
+	if !policy.Allows(newTestPolicyApp("org-acme", "community", "cert-manager-app", "2.0.0", "cert-manager")) {
+		t.Fatalf("Allows == false, want true for version matching constraint")
+	}
+
+	policy.Rules[0].Allow[0].Versions = ">=two"
+
+	if policy.Allows(newTestPolicyApp("org-acme", "community", "cert-manager-app", "2.0.0", "cert-manager")) {
+		t.Fatalf("Allows == true, want false for invalid constraint")
+	}
+
+	c := Config{
+		G8sClient: fake.NewSimpleClientset(),
+		K8sClient: clientgofake.NewSimpleClientset(),
+		Logger:    microloggertest.New(),
+
+		Policy: policy,
+
+		Provider: "aws",
+	}
+	_, err := NewValidator(c)
+	if !IsInvalidConfig(err) {
+		t.Fatalf("error == %#v, want invalid config error", err)
+	}
+	if !strings.Contains(err.Error(), "rules[0].allow[0].versions") {
+		t.Fatalf("error == %#v, want %#v ", err.Error(), "rules[0].allow[0].versions")
+	}
+}
+
+func newTestPolicyApp(namespace, catalog, name, version, targetNamespace string) v1alpha1.App {
+	return v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   catalog,
+			Name:      name,
+			Namespace: targetNamespace,
+			Version:   version,
+		},
+	}
+}
@@ -0,0 +1,128 @@
+package validation
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+const (
+	quotaCatalogSeparator = "="
+	quotaSeparator        = ","
+)
+
+// appQuota limits the number of app CRs in a namespace. Zero means
+// unlimited.
+type appQuota struct {
+	total    int
+	catalogs map[string]int
+}
+
+// validateQuota rejects app CRs that would exceed the app quota of their
+// namespace. Updates that do not change the catalog are not rejected so app
+// CRs in namespaces above their quota can still be updated.
+func (v *Validator) validateQuota(ctx context.Context, cr v1alpha1.App) error {
+	quota, err := v.namespaceAppQuota(ctx, cr.Namespace)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	catalogLimit := quota.catalogs[key.CatalogName(cr)]
+	if quota.total == 0 && catalogLimit == 0 {
+		// no-op
+		return nil
+	}
+
+	current, hasCurrent := currentAppFromContext(ctx)
+
+	apps, err := v.listApps(ctx, cr.Namespace)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	var count, catalogCount int
+	for _, app := range apps {
+		if app.Name == cr.Name {
+			continue
+		}
+
+		count++
+		if key.CatalogName(app) == key.CatalogName(cr) {
+			catalogCount++
+		}
+	}
+
+	if quota.total > 0 && !hasCurrent && count >= quota.total {
+		return resultErrorf(validationError, ReasonQuotaExceeded, "metadata.namespace", cr.Namespace, quotaExceededTemplate,
+			cr.Namespace, count, quota.total)
+	}
+
+	if catalogLimit > 0 && !(hasCurrent && key.CatalogName(current) == key.CatalogName(cr)) && catalogCount >= catalogLimit {
+		return resultErrorf(validationError, ReasonQuotaExceeded, "spec.catalog", key.CatalogName(cr), catalogQuotaExceededTemplate,
+			cr.Namespace, catalogCount, key.CatalogName(cr), catalogLimit)
+	}
+
+	return nil
+}
+
+// namespaceAppQuota returns the configured app quota overridden by the
+// annotations of the namespace when Config.NamespaceAppQuotas is set.
+func (v *Validator) namespaceAppQuota(ctx context.Context, namespace string) (appQuota, error) {
+	quota := appQuota{
+		total:    v.appQuota,
+		catalogs: map[string]int{},
+	}
+	for catalog, limit := range v.catalogAppQuotas {
+		quota.catalogs[catalog] = limit
+	}
+
+	if !v.namespaceAppQuotas {
+		return quota, nil
+	}
+
+	ns, err := v.getNamespace(ctx, namespace)
+	if err != nil {
+		return appQuota{}, microerror.Mask(err)
+	}
+	if ns == nil {
+		return quota, nil
+	}
+
+	if value := key.NamespaceAppQuota(*ns); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return appQuota{}, resultErrorf(validationError, ReasonQuotaInvalid, "metadata.namespace", namespace, quotaAnnotationInvalidTemplate,
+				namespace, key.NamespaceAppQuotaAnnotation, value)
+		}
+		quota.total = limit
+	}
+
+	if value := key.NamespaceCatalogAppQuotas(*ns); value != "" {
+		for _, entry := range strings.Split(value, quotaSeparator) {
+			entry = strings.TrimSpace(entry)
+			if entry == "" {
+				continue
+			}
+
+			parts := strings.SplitN(entry, quotaCatalogSeparator, 2)
+			if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+				return appQuota{}, resultErrorf(validationError, ReasonQuotaInvalid, "metadata.namespace", namespace, quotaAnnotationInvalidTemplate,
+					namespace, key.NamespaceCatalogAppQuotasAnnotation, value)
+			}
+
+			limit, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+			if err != nil || limit < 0 {
+				return appQuota{}, resultErrorf(validationError, ReasonQuotaInvalid, "metadata.namespace", namespace, quotaAnnotationInvalidTemplate,
+					namespace, key.NamespaceCatalogAppQuotasAnnotation, value)
+			}
+			quota.catalogs[strings.TrimSpace(parts[0])] = limit
+		}
+	}
+
+	return quota, nil
+}
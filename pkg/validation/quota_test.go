@@ -0,0 +1,247 @@
+package validation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+func Test_ValidateQuota(t *testing.T) {
+	ctx := context.Background()
+
+	newApp := func(name, catalog string) *v1alpha1.App {
+		return &v1alpha1.App{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "eggs2",
+			},
+			Spec: v1alpha1.AppSpec{
+				Catalog:   catalog,
+				Name:      name,
+				Namespace: "kube-system",
+			},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		obj              *v1alpha1.App
+		current          *v1alpha1.App
+		apps             []*v1alpha1.App
+		annotations      map[string]string
+		namespaceQuotas  bool
+		appQuota         int
+		catalogAppQuotas map[string]int
+		expectedErr      string
+	}{
+		{
+			name: "case 0: no quota",
+			obj:  newApp("kiam", "giantswarm"),
+			apps: []*v1alpha1.App{
+				newApp("cert-manager", "giantswarm"),
+			},
+		},
+		{
+			name: "case 1: within quota",
+			obj:  newApp("kiam", "giantswarm"),
+			apps: []*v1alpha1.App{
+				newApp("cert-manager", "giantswarm"),
+			},
+			appQuota: 2,
+		},
+		{
+			name: "case 2: quota exceeded",
+			obj:  newApp("kiam", "giantswarm"),
+			apps: []*v1alpha1.App{
+				newApp("cert-manager", "giantswarm"),
+				newApp("external-dns", "giantswarm"),
+			},
+			appQuota:    2,
+			expectedErr: "validation error: namespace `eggs2` has 2 app CRs and is limited to 2 app CRs",
+		},
+		{
+			name: "case 3: existing app is not counted twice",
+			obj:  newApp("kiam", "giantswarm"),
+			apps: []*v1alpha1.App{
+				newApp("cert-manager", "giantswarm"),
+				newApp("kiam", "giantswarm"),
+			},
+			appQuota: 2,
+		},
+		{
+			name: "case 4: namespace annotation overrides config",
+			obj:  newApp("kiam", "giantswarm"),
+			apps: []*v1alpha1.App{
+				newApp("cert-manager", "giantswarm"),
+			},
+			namespaceQuotas: true,
+			annotations: map[string]string{
+				key.NamespaceAppQuotaAnnotation: "1",
+			},
+			appQuota:    10,
+			expectedErr: "validation error: namespace `eggs2` has 1 app CRs and is limited to 1 app CRs",
+		},
+		{
+			name: "case 5: catalog quota exceeded",
+			obj:  newApp("kiam", "giantswarm"),
+			apps: []*v1alpha1.App{
+				newApp("cert-manager", "giantswarm"),
+				newApp("nginx", "community"),
+			},
+			catalogAppQuotas: map[string]int{
+				"giantswarm": 1,
+			},
+			expectedErr: "validation error: namespace `eggs2` has 1 app CRs from catalog `giantswarm` and is limited to 1 app CRs from this catalog",
+		},
+		{
+			name: "case 6: catalog quota of other catalog",
+			obj:  newApp("nginx", "community"),
+			apps: []*v1alpha1.App{
+				newApp("cert-manager", "giantswarm"),
+			},
+			namespaceQuotas: true,
+			annotations: map[string]string{
+				key.NamespaceCatalogAppQuotasAnnotation: "giantswarm=1, community=2",
+			},
+		},
+		{
+			name: "case 7: catalog quota from annotation",
+			obj:  newApp("kiam", "giantswarm"),
+			apps: []*v1alpha1.App{
+				newApp("cert-manager", "giantswarm"),
+			},
+			namespaceQuotas: true,
+			annotations: map[string]string{
+				key.NamespaceCatalogAppQuotasAnnotation: "giantswarm=1,community=2",
+			},
+			expectedErr: "validation error: namespace `eggs2` has 1 app CRs from catalog `giantswarm` and is limited to 1 app CRs from this catalog",
+		},
+		{
+			name:    "case 8: update in namespace over quota",
+			obj:     newApp("kiam", "giantswarm"),
+			current: newApp("kiam", "giantswarm"),
+			apps: []*v1alpha1.App{
+				newApp("cert-manager", "giantswarm"),
+				newApp("kiam", "giantswarm"),
+			},
+			appQuota: 1,
+			catalogAppQuotas: map[string]int{
+				"giantswarm": 1,
+			},
+		},
+		{
+			name:    "case 9: update moving app to catalog over quota",
+			obj:     newApp("kiam", "giantswarm"),
+			current: newApp("kiam", "community"),
+			apps: []*v1alpha1.App{
+				newApp("cert-manager", "giantswarm"),
+				newApp("kiam", "community"),
+			},
+			catalogAppQuotas: map[string]int{
+				"giantswarm": 1,
+			},
+			expectedErr: "validation error: namespace `eggs2` has 1 app CRs from catalog `giantswarm` and is limited to 1 app CRs from this catalog",
+		},
+		{
+			name:            "case 10: invalid quota annotation",
+			obj:             newApp("kiam", "giantswarm"),
+			namespaceQuotas: true,
+			annotations: map[string]string{
+				key.NamespaceAppQuotaAnnotation: "ten",
+			},
+			expectedErr: "validation error: namespace `eggs2` annotation `application.giantswarm.io/app-quota` has invalid value `ten`",
+		},
+		{
+			name:            "case 11: invalid catalog quotas annotation",
+			obj:             newApp("kiam", "giantswarm"),
+			namespaceQuotas: true,
+			annotations: map[string]string{
+				key.NamespaceCatalogAppQuotasAnnotation: "giantswarm",
+			},
+			expectedErr: "validation error: namespace `eggs2` annotation `application.giantswarm.io/catalog-app-quotas` has invalid value `giantswarm`",
+		},
+		{
+			name: "case 12: namespace annotations are ignored by default",
+			obj:  newApp("kiam", "giantswarm"),
+			apps: []*v1alpha1.App{
+				newApp("cert-manager", "giantswarm"),
+			},
+			annotations: map[string]string{
+				key.NamespaceAppQuotaAnnotation: "1",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g8sObjs := make([]runtime.Object, 0)
+			for _, app := range tc.apps {
+				g8sObjs = append(g8sObjs, app)
+			}
+
+			k8sClient := clientgofake.NewSimpleClientset(
+				&corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "eggs2",
+						Annotations: tc.annotations,
+					},
+				},
+			)
+
+			// Getting namespaces requires extra permissions and is only
+			// done when namespace quotas are enabled.
+			k8sClient.PrependReactor("get", "namespaces", func(action clienttesting.Action) (bool, runtime.Object, error) {
+				if !tc.namespaceQuotas {
+					t.Fatalf("namespace get, want none")
+				}
+				return false, nil, nil
+			})
+
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(g8sObjs...),
+				K8sClient: k8sClient,
+				Logger:    microloggertest.New(),
+
+				AppQuota:           tc.appQuota,
+				CatalogAppQuotas:   tc.catalogAppQuotas,
+				EnabledRules:       []string{RuleQuota},
+				NamespaceAppQuotas: tc.namespaceQuotas,
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			if tc.current != nil {
+				_, err = r.ValidateAppUpdate(ctx, *tc.current, *tc.obj)
+			} else {
+				_, err = r.ValidateAppWithWarnings(ctx, *tc.obj)
+			}
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil && tc.expectedErr != "" {
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package validation
+
+import (
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+// ReferencePolicy controls which namespaces app CRs may reference configmaps
+// and secrets in. References to the namespace of the app CR are always
+// allowed.
+type ReferencePolicy string
+
+const (
+	// ReferencePolicyAny allows references to any namespace.
+	ReferencePolicyAny ReferencePolicy = "Any"
+	// ReferencePolicySameNamespace only allows references to the namespace
+	// of the app CR.
+	ReferencePolicySameNamespace ReferencePolicy = "SameNamespace"
+	// ReferencePolicyAllowList also allows references to the namespaces in
+	// Config.ReferenceAllowedNamespaces.
+	ReferencePolicyAllowList ReferencePolicy = "AllowList"
+	// ReferencePolicyOptIn also allows references to the namespaces in
+	// Config.ReferenceAllowedNamespaces and to resources labelled with
+	// key.CrossNamespaceReferenceLabel.
+	ReferencePolicyOptIn ReferencePolicy = "OptIn"
+)
+
+var referencePolicies = []ReferencePolicy{
+	ReferencePolicyAny,
+	ReferencePolicySameNamespace,
+	ReferencePolicyAllowList,
+	ReferencePolicyOptIn,
+}
+
+// validateReference checks the app CR may reference the resource in the
+// given namespace. obj is the referenced resource or nil if it is not found.
+// It is called before reporting missing resources so tenants cannot probe
+// for resources in namespaces of other tenants.
+func (v *Validator) validateReference(cr v1alpha1.App, field, kind, name, namespace string, obj metav1.Object) error {
+	if v.referencePolicy == ReferencePolicyAny || namespace == cr.Namespace {
+		return nil
+	}
+
+	switch v.referencePolicy {
+	case ReferencePolicyAllowList:
+		if containsString(v.referenceAllowedNamespaces, namespace) {
+			return nil
+		}
+	case ReferencePolicyOptIn:
+		if containsString(v.referenceAllowedNamespaces, namespace) {
+			return nil
+		}
+		if obj != nil && key.CrossNamespaceReferenceAllowed(obj) {
+			return nil
+		}
+	}
+
+	return resultErrorf(validationError, ReasonReferenceNotAllowed, field, namespace, referenceNotAllowedTemplate,
+		cr.Name, cr.Namespace, kind, name, namespace, v.referencePolicy)
+}
+
+// configMapObject avoids passing a typed nil as metav1.Object.
+func configMapObject(configMap *corev1.ConfigMap) metav1.Object {
+	if configMap == nil {
+		return nil
+	}
+	return configMap
+}
+
+// secretObject avoids passing a typed nil as metav1.Object.
+func secretObject(secret *corev1.Secret) metav1.Object {
+	if secret == nil {
+		return nil
+	}
+	return secret
+}
+
+func containsReferencePolicy(s []ReferencePolicy, e ReferencePolicy) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
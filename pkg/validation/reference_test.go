@@ -0,0 +1,184 @@
+package validation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+func Test_ValidateReference(t *testing.T) {
+	ctx := context.Background()
+
+	newSecret := func(name, namespace string, labels map[string]string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    labels,
+			},
+			Data: map[string][]byte{
+				"values": []byte("replicas: 2\n"),
+			},
+		}
+	}
+
+	tests := []struct {
+		name              string
+		secretNamespace   string
+		secrets           []*corev1.Secret
+		policy            ReferencePolicy
+		allowedNamespaces []string
+		expectedErr       string
+	}{
+		{
+			name:            "case 0: any namespace by default",
+			secretNamespace: "org-other",
+			secrets: []*corev1.Secret{
+				newSecret("kiam-user-secrets", "org-other", nil),
+			},
+		},
+		{
+			name:            "case 1: same namespace",
+			secretNamespace: "org-acme",
+			secrets: []*corev1.Secret{
+				newSecret("kiam-user-secrets", "org-acme", nil),
+			},
+			policy: ReferencePolicySameNamespace,
+		},
+		{
+			name:            "case 2: other namespace with same namespace policy",
+			secretNamespace: "org-other",
+			secrets: []*corev1.Secret{
+				newSecret("kiam-user-secrets", "org-other", nil),
+			},
+			policy:      ReferencePolicySameNamespace,
+			expectedErr: "validation error: app `kiam` in namespace `org-acme` must not reference secret `kiam-user-secrets` in namespace `org-other` with reference policy `SameNamespace`",
+		},
+		{
+			name:            "case 3: allowed namespace",
+			secretNamespace: "giantswarm",
+			secrets: []*corev1.Secret{
+				newSecret("kiam-user-secrets", "giantswarm", nil),
+			},
+			policy:            ReferencePolicyAllowList,
+			allowedNamespaces: []string{"giantswarm"},
+		},
+		{
+			name:            "case 4: namespace not in allow list",
+			secretNamespace: "org-other",
+			secrets: []*corev1.Secret{
+				newSecret("kiam-user-secrets", "org-other", nil),
+			},
+			policy:            ReferencePolicyAllowList,
+			allowedNamespaces: []string{"giantswarm"},
+			expectedErr:       "validation error: app `kiam` in namespace `org-acme` must not reference secret `kiam-user-secrets` in namespace `org-other` with reference policy `AllowList`",
+		},
+		{
+			name:            "case 5: allow list ignores opt-in label",
+			secretNamespace: "org-other",
+			secrets: []*corev1.Secret{
+				newSecret("kiam-user-secrets", "org-other", map[string]string{key.CrossNamespaceReferenceLabel: "true"}),
+			},
+			policy:      ReferencePolicyAllowList,
+			expectedErr: "validation error: app `kiam` in namespace `org-acme` must not reference secret `kiam-user-secrets` in namespace `org-other` with reference policy `AllowList`",
+		},
+		{
+			name:            "case 6: opted-in secret",
+			secretNamespace: "org-other",
+			secrets: []*corev1.Secret{
+				newSecret("kiam-user-secrets", "org-other", map[string]string{key.CrossNamespaceReferenceLabel: "true"}),
+			},
+			policy: ReferencePolicyOptIn,
+		},
+		{
+			name:            "case 7: secret not opted in",
+			secretNamespace: "org-other",
+			secrets: []*corev1.Secret{
+				newSecret("kiam-user-secrets", "org-other", nil),
+			},
+			policy:      ReferencePolicyOptIn,
+			expectedErr: "validation error: app `kiam` in namespace `org-acme` must not reference secret `kiam-user-secrets` in namespace `org-other` with reference policy `OptIn`",
+		},
+		{
+			name:            "case 8: missing secret in other namespace is not revealed",
+			secretNamespace: "org-other",
+			policy:          ReferencePolicyOptIn,
+			expectedErr:     "validation error: app `kiam` in namespace `org-acme` must not reference secret `kiam-user-secrets` in namespace `org-other` with reference policy `OptIn`",
+		},
+		{
+			name:            "case 9: missing secret in allowed namespace",
+			secretNamespace: "giantswarm",
+			policy:          ReferencePolicyOptIn,
+			allowedNamespaces: []string{
+				"giantswarm",
+			},
+			expectedErr: "validation error: secret `kiam-user-secrets` in namespace `giantswarm` not found",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kiam",
+					Namespace: "org-acme",
+				},
+				Spec: v1alpha1.AppSpec{
+					Name:      "kiam",
+					Namespace: "kube-system",
+					UserConfig: v1alpha1.AppSpecUserConfig{
+						Secret: v1alpha1.AppSpecUserConfigSecret{
+							Name:      "kiam-user-secrets",
+							Namespace: tc.secretNamespace,
+						},
+					},
+				},
+			}
+
+			k8sObjs := make([]runtime.Object, 0)
+			for _, secret := range tc.secrets {
+				k8sObjs = append(k8sObjs, secret)
+			}
+
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(),
+				K8sClient: clientgofake.NewSimpleClientset(k8sObjs...),
+				Logger:    microloggertest.New(),
+
+				EnabledRules:               []string{RuleUserConfig},
+				ReferenceAllowedNamespaces: tc.allowedNamespaces,
+				ReferencePolicy:            tc.policy,
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			_, err = r.ValidateApp(ctx, obj)
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil && tc.expectedErr != "" {
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package validation
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+// validateRelease rejects app CRs installing a Helm release that is already
+// owned by another app CR. Helm releases are identified by their name and
+// namespace in the target cluster, so app CRs in all namespaces targeting the
+// same cluster are checked. Without a synced app informer this lists the
+// app CRs of all namespaces, see Config.AppInformer.
+func (v *Validator) validateRelease(ctx context.Context, cr v1alpha1.App) error {
+	if key.ReleaseName(cr) == "" || key.AppNamespace(cr) == "" {
+		// Checked by the name rule.
+		return nil
+	}
+
+	apps, err := v.listAppsByRelease(ctx, cr)
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	current, hasCurrent := currentAppFromContext(ctx)
+
+	for _, app := range apps {
+		if app.Namespace == cr.Namespace && app.Name == cr.Name {
+			continue
+		}
+
+		// Collisions that already exist before the update are only a
+		// warning so the app CR can still be updated, e.g. to fix the
+		// collision.
+		if hasCurrent && releaseKey(current) == releaseKey(cr) {
+			AddWarning(ctx, releaseCollisionWarningTemplate, key.ReleaseName(cr), key.AppNamespace(cr), targetCluster(cr), app.Name, app.Namespace)
+			continue
+		}
+
+		return resultErrorf(validationError, ReasonReleaseCollision, "spec.name", key.ReleaseName(cr), releaseCollisionTemplate,
+			key.ReleaseName(cr), key.AppNamespace(cr), targetCluster(cr), app.Name, app.Namespace)
+	}
+
+	return nil
+}
+
+// releaseKey identifies the Helm release installed by the app CR.
+func releaseKey(cr v1alpha1.App) string {
+	return appKey(appKey(targetCluster(cr), key.AppNamespace(cr)), key.ReleaseName(cr))
+}
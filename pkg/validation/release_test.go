@@ -0,0 +1,222 @@
+package validation
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func Test_ValidateRelease(t *testing.T) {
+	ctx := context.Background()
+
+	newApp := func(name, namespace, releaseName, targetNamespace, secretNamespace string) *v1alpha1.App {
+		return &v1alpha1.App{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: v1alpha1.AppSpec{
+				Name:      releaseName,
+				Namespace: targetNamespace,
+				KubeConfig: v1alpha1.AppSpecKubeConfig{
+					Secret: v1alpha1.AppSpecKubeConfigSecret{
+						Name:      secretNamespace + "-kubeconfig",
+						Namespace: secretNamespace,
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name             string
+		obj              *v1alpha1.App
+		current          *v1alpha1.App
+		apps             []*v1alpha1.App
+		expectedWarnings []string
+		expectedErr      string
+	}{
+		{
+			name: "case 0: no other apps",
+			obj:  newApp("kiam", "eggs2", "kiam", "kube-system", "eggs2"),
+		},
+		{
+			name: "case 1: release owned by app in another namespace",
+			obj:  newApp("kiam", "org-acme", "kiam", "kube-system", "eggs2"),
+			apps: []*v1alpha1.App{
+				newApp("kiam", "eggs2", "kiam", "kube-system", "eggs2"),
+			},
+			expectedErr: "validation error: helm release `kiam` in namespace `kube-system` of cluster `eggs2/eggs2-kubeconfig` is already owned by app `kiam` in namespace `eggs2`",
+		},
+		{
+			name: "case 2: same release name in another target namespace",
+			obj:  newApp("kiam", "eggs2", "kiam", "kube-system", "eggs2"),
+			apps: []*v1alpha1.App{
+				newApp("kiam-monitoring", "eggs2", "kiam", "monitoring", "eggs2"),
+			},
+		},
+		{
+			name: "case 3: same release in another cluster",
+			obj:  newApp("kiam", "eggs2", "kiam", "kube-system", "eggs2"),
+			apps: []*v1alpha1.App{
+				newApp("kiam", "eggs3", "kiam", "kube-system", "eggs3"),
+			},
+		},
+		{
+			name:    "case 4: update taking over a release",
+			obj:     newApp("other", "eggs2", "kiam", "kube-system", "eggs2"),
+			current: newApp("other", "eggs2", "other", "kube-system", "eggs2"),
+			apps: []*v1alpha1.App{
+				newApp("kiam", "eggs2", "kiam", "kube-system", "eggs2"),
+			},
+			expectedErr: "validation error: helm release `kiam` in namespace `kube-system` of cluster `eggs2/eggs2-kubeconfig` is already owned by app `kiam` in namespace `eggs2`",
+		},
+		{
+			name:    "case 5: update of app with existing collision",
+			obj:     newApp("other", "eggs2", "kiam", "kube-system", "eggs2"),
+			current: newApp("other", "eggs2", "kiam", "kube-system", "eggs2"),
+			apps: []*v1alpha1.App{
+				newApp("kiam", "eggs2", "kiam", "kube-system", "eggs2"),
+			},
+			expectedWarnings: []string{
+				"helm release `kiam` in namespace `kube-system` of cluster `eggs2/eggs2-kubeconfig` is also installed by app `kiam` in namespace `eggs2`",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g8sObjs := make([]runtime.Object, 0)
+			for _, app := range tc.apps {
+				g8sObjs = append(g8sObjs, app)
+			}
+
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(g8sObjs...),
+				K8sClient: clientgofake.NewSimpleClientset(),
+				Logger:    microloggertest.New(),
+
+				EnabledRules: []string{RuleRelease},
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			var warnings []string
+			if tc.current != nil {
+				warnings, err = r.ValidateAppUpdate(ctx, *tc.current, *tc.obj)
+			} else {
+				warnings, err = r.ValidateAppWithWarnings(ctx, *tc.obj)
+			}
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil && tc.expectedErr != "" {
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+			}
+
+			if !reflect.DeepEqual(warnings, tc.expectedWarnings) {
+				t.Fatalf("warnings == %#v, want %#v", warnings, tc.expectedWarnings)
+			}
+		})
+	}
+}
+
+func Test_ValidateRelease_ClusterWideList(t *testing.T) {
+	ctx := context.Background()
+
+	obj := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kiam",
+			Namespace: "eggs2",
+		},
+		Spec: v1alpha1.AppSpec{
+			Name:      "kiam",
+			Namespace: "kube-system",
+			KubeConfig: v1alpha1.AppSpecKubeConfig{
+				InCluster: true,
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		withInformer  bool
+		expectedLists float64
+	}{
+		{
+			name:          "case 0: without informer",
+			expectedLists: 1,
+		},
+		{
+			name:          "case 1: with synced informer",
+			withInformer:  true,
+			expectedLists: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g8sClient := fake.NewSimpleClientset()
+
+			c := Config{
+				G8sClient: g8sClient,
+				K8sClient: clientgofake.NewSimpleClientset(),
+				Logger:    microloggertest.New(),
+
+				EnabledRules: []string{RuleRelease},
+
+				Provider: "aws",
+			}
+			if tc.withInformer {
+				c.AppInformer = NewAppInformer(g8sClient, 0)
+			}
+
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			if tc.withInformer {
+				stopCh := make(chan struct{})
+				defer close(stopCh)
+
+				// Indexers are added by NewValidator so the informer is
+				// started afterwards.
+				go c.AppInformer.Run(stopCh)
+				if !cache.WaitForCacheSync(stopCh, c.AppInformer.HasSynced) {
+					t.Fatalf("app informer not synced")
+				}
+			}
+
+			_, err = r.ValidateApp(ctx, obj)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			lists := testutil.ToFloat64(r.metrics.clusterWideLists.WithLabelValues(appReleaseIndex))
+			if lists != tc.expectedLists {
+				t.Fatalf("cluster wide lists == %v, want %v", lists, tc.expectedLists)
+			}
+		})
+	}
+}
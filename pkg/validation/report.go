@@ -0,0 +1,176 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+const (
+	defaultConcurrency = 10
+)
+
+// Report holds the outcome of validating all app CRs of a namespace or
+// cluster. See Validator.ValidateAll.
+type Report struct {
+	Apps []AppReport `json:"apps"`
+}
+
+// AppReport is the outcome of validating one app CR.
+type AppReport struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Status is accepted, rejected when the app CR is invalid or failed when
+	// it could not be validated, e.g. because the Kubernetes API could not
+	// be reached.
+	Status string `json:"status"`
+	// Result describes why the app CR was rejected. It is nil for errors
+	// without a result.
+	Result *Result `json:"result,omitempty"`
+	// Error is the message of the error rejecting the app CR or failing its
+	// validation.
+	Error    string   `json:"error,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ValidateAll validates all app CRs in the namespace or in all namespaces
+// when namespace is empty. This finds app CRs which were valid when they
+// were admitted but have since been invalidated, e.g. by deleted catalogs or
+// secrets. App CRs are validated concurrently, see Config.Concurrency. The
+// report is sorted by namespace and name. App CRs are validated like
+// unchanged updates, see ValidateAppUpdate.
+func (v *Validator) ValidateAll(ctx context.Context, namespace string) (*Report, error) {
+	apps, err := v.listApps(ctx, namespace)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	sort.Slice(apps, func(i, j int) bool {
+		return appKey(apps[i].Namespace, apps[i].Name) < appKey(apps[j].Namespace, apps[j].Name)
+	})
+
+	report := &Report{
+		Apps: make([]AppReport, len(apps)),
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, v.concurrency)
+
+	for i := range apps {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, app v1alpha1.App) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			report.Apps[i] = v.validateForReport(ctx, app)
+		}(i, apps[i])
+	}
+
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, microerror.Mask(ctx.Err())
+	}
+
+	return report, nil
+}
+
+// validateForReport validates the existing app CR as an unchanged update
+// of itself so findings it was already admitted with, such as expired
+// cordons, are reported as warnings like on updates. Report runs are not
+// counted as admission validations.
+func (v *Validator) validateForReport(ctx context.Context, app v1alpha1.App) AppReport {
+	ctx = context.WithValue(ctx, reportKey{}, true)
+
+	warnings, err := v.ValidateAppUpdate(ctx, app, app)
+
+	r := AppReport{
+		Namespace: app.Namespace,
+		Name:      app.Name,
+		Status:    validationStatus(err),
+		Warnings:  warnings,
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	if result, ok := ResultFromError(err); ok {
+		r.Result = &result
+	}
+
+	return r
+}
+
+type reportKey struct{}
+
+// isReport returns whether the app CR is validated for a report.
+func isReport(ctx context.Context) bool {
+	report, _ := ctx.Value(reportKey{}).(bool)
+	return report
+}
+
+// Invalid returns the reports of the app CRs which were not accepted.
+func (r *Report) Invalid() []AppReport {
+	var invalid []AppReport
+	for _, a := range r.Apps {
+		if a.Status != resultAccepted {
+			invalid = append(invalid, a)
+		}
+	}
+
+	return invalid
+}
+
+// JSON returns the report as indented JSON.
+func (r *Report) JSON() ([]byte, error) {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return b, nil
+}
+
+// WriteTable writes the report as a table with one row per app CR.
+func (r *Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	_, err := fmt.Fprintln(tw, "NAMESPACE\tNAME\tSTATUS\tREASON\tWARNINGS\tMESSAGE")
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	for _, a := range r.Apps {
+		reason := "-"
+		message := a.Error
+		if a.Result != nil {
+			reason = string(a.Result.Reason)
+			message = a.Result.Message
+		}
+		if message == "" {
+			message = "-"
+		}
+
+		_, err = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\n", a.Namespace, a.Name, a.Status, reason, len(a.Warnings), message)
+		if err != nil {
+			return microerror.Mask(err)
+		}
+	}
+
+	err = tw.Flush()
+	if err != nil {
+		return microerror.Mask(err)
+	}
+
+	return nil
+}
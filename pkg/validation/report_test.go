@@ -0,0 +1,231 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	"github.com/giantswarm/micrologger/microloggertest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+func Test_ValidateAll(t *testing.T) {
+	ctx := context.Background()
+
+	newApp := func(name, namespace, catalog string) *v1alpha1.App {
+		return &v1alpha1.App{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: v1alpha1.AppSpec{
+				Catalog:   catalog,
+				Name:      name,
+				Namespace: "kube-system",
+			},
+		}
+	}
+
+	g8sObjs := []runtime.Object{
+		newTestCatalog("giantswarm", "giantswarm"),
+		newApp("kiam", "eggs2", "giantswarm"),
+		newApp("cert-manager", "eggs2", "deleted"),
+		newApp("external-dns", "eggs3", "giantswarm"),
+	}
+
+	tests := []struct {
+		name             string
+		namespace        string
+		expectedApps     []string
+		expectedStatuses []string
+		expectedInvalid  []string
+	}{
+		{
+			name:             "case 0: all namespaces",
+			namespace:        metav1.NamespaceAll,
+			expectedApps:     []string{"eggs2/cert-manager", "eggs2/kiam", "eggs3/external-dns"},
+			expectedStatuses: []string{resultRejected, resultAccepted, resultAccepted},
+			expectedInvalid:  []string{"eggs2/cert-manager"},
+		},
+		{
+			name:             "case 1: one namespace",
+			namespace:        "eggs3",
+			expectedApps:     []string{"eggs3/external-dns"},
+			expectedStatuses: []string{resultAccepted},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(g8sObjs...),
+				K8sClient: clientgofake.NewSimpleClientset(),
+				Logger:    microloggertest.New(),
+
+				Concurrency:  2,
+				EnabledRules: []string{RuleCatalog, RuleName},
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			report, err := r.ValidateAll(ctx, tc.namespace)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			var apps, statuses []string
+			for _, a := range report.Apps {
+				apps = append(apps, appKey(a.Namespace, a.Name))
+				statuses = append(statuses, a.Status)
+			}
+			if !reflect.DeepEqual(apps, tc.expectedApps) {
+				t.Fatalf("apps == %#v, want %#v", apps, tc.expectedApps)
+			}
+			if !reflect.DeepEqual(statuses, tc.expectedStatuses) {
+				t.Fatalf("statuses == %#v, want %#v", statuses, tc.expectedStatuses)
+			}
+
+			var invalid []string
+			for _, a := range report.Invalid() {
+				invalid = append(invalid, appKey(a.Namespace, a.Name))
+			}
+			if !reflect.DeepEqual(invalid, tc.expectedInvalid) {
+				t.Fatalf("invalid == %#v, want %#v", invalid, tc.expectedInvalid)
+			}
+		})
+	}
+}
+
+func Test_ValidateAll_ExistingApps(t *testing.T) {
+	ctx := context.Background()
+
+	expired := time.Now().UTC().Add(-48 * time.Hour).Format(key.CordonUntilDateLayout)
+
+	app := &v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kiam",
+			Namespace: "eggs2",
+			Annotations: map[string]string{
+				annotation.AppOperatorCordonReason: "manual upgrade",
+				annotation.AppOperatorCordonUntil:  expired,
+			},
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "giantswarm",
+			Name:      "kiam",
+			Namespace: "kube-system",
+		},
+	}
+
+	c := Config{
+		G8sClient: fake.NewSimpleClientset(app),
+		K8sClient: clientgofake.NewSimpleClientset(),
+		Logger:    microloggertest.New(),
+
+		EnabledRules: []string{RuleCordon},
+
+		Provider: "aws",
+	}
+	r, err := NewValidator(c)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	report, err := r.ValidateAll(ctx, metav1.NamespaceAll)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	expectedApps := []AppReport{
+		{
+			Namespace: "eggs2",
+			Name:      "kiam",
+			Status:    resultAccepted,
+			Warnings: []string{
+				"app `kiam` cordon annotation `" + annotation.AppOperatorCordonUntil + "` expired at `" + expired + "`",
+			},
+		},
+	}
+	if !reflect.DeepEqual(report.Apps, expectedApps) {
+		t.Fatalf("apps == %#v, want %#v", report.Apps, expectedApps)
+	}
+
+	// Report runs are not admissions.
+	if count := testutil.CollectAndCount(r.metrics.validations); count != 0 {
+		t.Fatalf("validations == %d, want 0", count)
+	}
+}
+
+func Test_Report_Output(t *testing.T) {
+	report := &Report{
+		Apps: []AppReport{
+			{
+				Namespace: "eggs2",
+				Name:      "cert-manager",
+				Status:    resultRejected,
+				Result: &Result{
+					Reason:  ReasonCatalogNotFound,
+					Field:   "spec.catalog",
+					Value:   "deleted",
+					Message: "catalog `deleted` not found",
+				},
+				Error: "validation error: catalog `deleted` not found (reason: CatalogNotFound, field: spec.catalog, value: \"deleted\")",
+			},
+			{
+				Namespace: "eggs2",
+				Name:      "kiam",
+				Status:    resultAccepted,
+				Warnings:  []string{"app `kiam` version `1.4.0` in catalog `giantswarm` is deprecated"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := report.WriteTable(&buf)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	expectedTable := strings.Join([]string{
+		"NAMESPACE  NAME          STATUS    REASON           WARNINGS  MESSAGE",
+		"eggs2      cert-manager  rejected  CatalogNotFound  0         catalog `deleted` not found",
+		"eggs2      kiam          accepted  -                1         -",
+		"",
+	}, "\n")
+	if buf.String() != expectedTable {
+		t.Fatalf("table == %q, want %q", buf.String(), expectedTable)
+	}
+
+	b, err := report.JSON()
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+
+	var decoded Report
+	err = json.Unmarshal(b, &decoded)
+	if err != nil {
+		t.Fatalf("error == %#v, want nil", err)
+	}
+	if !reflect.DeepEqual(&decoded, report) {
+		t.Fatalf("report == %#v, want %#v", decoded, report)
+	}
+	if !strings.Contains(string(b), `"reason": "CatalogNotFound"`) {
+		t.Fatalf("json == %s, want reason", b)
+	}
+}
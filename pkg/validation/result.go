@@ -0,0 +1,178 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/giantswarm/microerror"
+)
+
+// Reason is a stable code describing why an app CR was rejected. Reasons are
+// part of the API and must not be renamed.
+type Reason string
+
+const (
+	ReasonCatalogNotFound              Reason = "CatalogNotFound"
+	ReasonCordonInvalid                Reason = "CordonInvalid"
+	ReasonDependencyCycle              Reason = "DependencyCycle"
+	ReasonDependencyInvalid            Reason = "DependencyInvalid"
+	ReasonDependencyNotFound           Reason = "DependencyNotFound"
+	ReasonDependencyVersionTooLow      Reason = "DependencyVersionTooLow"
+	ReasonKubeConfigInvalid            Reason = "KubeConfigInvalid"
+	ReasonKubeVersionIncompatible      Reason = "KubeVersionIncompatible"
+	ReasonKubeVersionInvalidConstraint Reason = "KubeVersionInvalidConstraint"
+	ReasonLabelInvalid                 Reason = "LabelInvalid"
+	ReasonLabelNotFound                Reason = "LabelNotFound"
+	ReasonNameEmpty                    Reason = "NameEmpty"
+	ReasonNameInvalid                  Reason = "NameInvalid"
+	ReasonNameTooLong                  Reason = "NameTooLong"
+	ReasonNamespaceConfigCollision     Reason = "NamespaceConfigCollision"
+	ReasonNamespaceConfigDenied        Reason = "NamespaceConfigDenied"
+	ReasonNamespaceConfigInvalid       Reason = "NamespaceConfigInvalid"
+	ReasonNamespaceNotAllowed          Reason = "NamespaceNotAllowed"
+	ReasonNamespaceNotSpecified        Reason = "NamespaceNotSpecified"
+	ReasonPolicyNotAllowed             Reason = "PolicyNotAllowed"
+	ReasonProviderNotAllowed           Reason = "ProviderNotAllowed"
+	ReasonQuotaExceeded                Reason = "QuotaExceeded"
+	ReasonQuotaInvalid                 Reason = "QuotaInvalid"
+	ReasonReferenceNotAllowed          Reason = "ReferenceNotAllowed"
+	ReasonReleaseCollision             Reason = "ReleaseCollision"
+	ReasonResourceInvalid              Reason = "ResourceInvalid"
+	ReasonResourceNotFound             Reason = "ResourceNotFound"
+	ReasonSingletonViolation           Reason = "SingletonViolation"
+	ReasonUserConfigNameInvalid        Reason = "UserConfigNameInvalid"
+)
+
+var (
+	// resultPattern matches the message of a validation error as returned
+	// by Error and by the admission controller. The kind prefix and the
+	// admission controller text are optional.
+	resultPattern = regexp.MustCompile(`^(?:[\d\D]*` + regexp.QuoteMeta(appAdmissionControllerText) + `)?\s*(?:[a-z ]+ error: )?([\d\D]*?) \(reason: ([A-Za-z]+)(?:, field: ([^,()]+))?(?:, value: ("(?:[^"\\]|\\.)*"))?\)$`)
+)
+
+// Result is the machine-readable form of a validation error.
+type Result struct {
+	// Reason is the stable code of the failed check.
+	Reason Reason `json:"reason"`
+	// Field is the path of the offending field, e.g.
+	// spec.userConfig.configMap.name.
+	Field string `json:"field,omitempty"`
+	// Value is the offending value.
+	Value string `json:"value,omitempty"`
+	// Message is the human readable message.
+	Message string `json:"message"`
+}
+
+// JSON returns the JSON form of the result.
+func (r Result) JSON() string {
+	b, err := json.Marshal(r)
+	if err != nil {
+		// Result only holds strings so marshalling cannot fail.
+		panic(err)
+	}
+
+	return string(b)
+}
+
+// String returns the message followed by the reason, field and value. This
+// is the form used in error messages so it can be parsed by ParseResult.
+func (r Result) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(r.Message)
+	sb.WriteString(" (reason: ")
+	sb.WriteString(string(r.Reason))
+	if r.Field != "" {
+		sb.WriteString(", field: ")
+		sb.WriteString(r.Field)
+	}
+	if r.Value != "" {
+		sb.WriteString(", value: ")
+		sb.WriteString(strconv.Quote(r.Value))
+	}
+	sb.WriteString(")")
+
+	return sb.String()
+}
+
+// ResultFromError returns the result carried by a validation error. It
+// returns false if err was not returned by a validation rule.
+func ResultFromError(err error) (Result, bool) {
+	var rerr *resultError
+	if errors.As(err, &rerr) {
+		return rerr.result, true
+	}
+
+	return Result{}, false
+}
+
+// ParseResult rebuilds the result from the message of an admission
+// rejection, e.g. the error returned by the Kubernetes API when an app CR
+// is denied by app-admission-controller. It returns false if the message
+// does not hold a result.
+func ParseResult(message string) (Result, bool) {
+	matches := resultPattern.FindStringSubmatch(strings.TrimSpace(message))
+	if matches == nil {
+		return Result{}, false
+	}
+
+	r := Result{
+		Message: matches[1],
+		Reason:  Reason(matches[2]),
+		Field:   matches[3],
+	}
+
+	if matches[4] != "" {
+		value, err := strconv.Unquote(matches[4])
+		if err != nil {
+			return Result{}, false
+		}
+		r.Value = value
+	}
+
+	return r, true
+}
+
+// resultError is a validation error carrying a result. It unwraps to its
+// kind so the Is* functions keep working.
+type resultError struct {
+	kind   *microerror.Error
+	result Result
+}
+
+func (e *resultError) Error() string {
+	return fmt.Sprintf("%s: %s", e.kind.Error(), e.result.String())
+}
+
+func (e *resultError) Unwrap() error {
+	return e.kind
+}
+
+// resultErrorf returns an error of the given kind carrying a result with
+// the formatted message.
+func resultErrorf(kind *microerror.Error, reason Reason, field, value string, format string, args ...interface{}) error {
+	return microerror.Mask(&resultError{
+		kind: kind,
+		result: Result{
+			Reason:  reason,
+			Field:   field,
+			Value:   value,
+			Message: fmt.Sprintf(format, args...),
+		},
+	})
+}
+
+// annotationField returns the field path of the annotation with the given
+// name.
+func annotationField(name string) string {
+	return fmt.Sprintf("metadata.annotations[%s]", name)
+}
+
+// labelField returns the field path of the label with the given name.
+func labelField(name string) string {
+	return fmt.Sprintf("metadata.labels[%s]", name)
+}
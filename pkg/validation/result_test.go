@@ -0,0 +1,167 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_Result(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		obj            v1alpha1.App
+		rules          []string
+		expectedResult Result
+		expectedJSON   string
+	}{
+		{
+			name: "case 0: catalog not found",
+			obj: v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kiam",
+					Namespace: "eggs2",
+				},
+				Spec: v1alpha1.AppSpec{
+					Catalog: "missing",
+				},
+			},
+			rules: []string{RuleCatalog},
+			expectedResult: Result{
+				Reason:  ReasonCatalogNotFound,
+				Field:   "spec.catalog",
+				Value:   "missing",
+				Message: "catalog `missing` not found",
+			},
+			expectedJSON: `{"reason":"CatalogNotFound","field":"spec.catalog","value":"missing","message":"catalog ` + "`missing`" + ` not found"}`,
+		},
+		{
+			name: "case 1: user configmap not found",
+			obj: v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kiam",
+					Namespace: "eggs2",
+				},
+				Spec: v1alpha1.AppSpec{
+					UserConfig: v1alpha1.AppSpecUserConfig{
+						ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+							Name:      "kiam-user-values",
+							Namespace: "eggs2",
+						},
+					},
+				},
+			},
+			rules: []string{RuleUserConfig},
+			expectedResult: Result{
+				Reason:  ReasonResourceNotFound,
+				Field:   "spec.userConfig.configMap.name",
+				Value:   "kiam-user-values",
+				Message: "configmap `kiam-user-values` in namespace `eggs2` not found",
+			},
+			expectedJSON: `{"reason":"ResourceNotFound","field":"spec.userConfig.configMap.name","value":"kiam-user-values","message":"configmap ` + "`kiam-user-values`" + ` in namespace ` + "`eggs2`" + ` not found"}`,
+		},
+		{
+			name: "case 2: label not found without value",
+			obj: v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kiam",
+					Namespace: "eggs2",
+				},
+			},
+			rules: []string{RuleLabels},
+			expectedResult: Result{
+				Reason:  ReasonLabelNotFound,
+				Field:   "metadata.labels[app-operator.giantswarm.io/version]",
+				Message: "label `app-operator.giantswarm.io/version` not found",
+			},
+			expectedJSON: `{"reason":"LabelNotFound","field":"metadata.labels[app-operator.giantswarm.io/version]","message":"label ` + "`app-operator.giantswarm.io/version`" + ` not found"}`,
+		},
+		{
+			name: "case 3: value with special characters",
+			obj: v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "Kiam, \"the\" (app)",
+					Namespace: "eggs2",
+				},
+			},
+			rules: []string{RuleName},
+			expectedResult: Result{
+				Reason:  ReasonNameInvalid,
+				Field:   "metadata.name",
+				Value:   "Kiam, \"the\" (app)",
+				Message: "metadata.name `Kiam, \"the\" (app)` is not a valid DNS-1123 subdomain: a DNS-1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(),
+				K8sClient: clientgofake.NewSimpleClientset(),
+				Logger:    microloggertest.New(),
+
+				EnabledRules: tc.rules,
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			_, err = r.ValidateApp(ctx, tc.obj)
+			if err == nil {
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			result, ok := ResultFromError(err)
+			if !ok {
+				t.Fatalf("ResultFromError(%#q) returned false, want true", err.Error())
+			}
+			if !reflect.DeepEqual(result, tc.expectedResult) {
+				t.Fatalf("result == %#v, want %#v", result, tc.expectedResult)
+			}
+
+			if tc.expectedJSON != "" && result.JSON() != tc.expectedJSON {
+				t.Fatalf("json == %#q, want %#q", result.JSON(), tc.expectedJSON)
+			}
+
+			// The admission controller returns the error message which
+			// the Kubernetes API wraps.
+			message := fmt.Sprintf("admission webhook \"apps.app-admission-controller.giantswarm.io\" denied the request: %s\n", err.Error())
+			parsed, ok := ParseResult(message)
+			if !ok {
+				t.Fatalf("ParseResult(%#q) returned false, want true", message)
+			}
+			if !reflect.DeepEqual(parsed, tc.expectedResult) {
+				t.Fatalf("parsed result == %#v, want %#v", parsed, tc.expectedResult)
+			}
+		})
+	}
+}
+
+func Test_ParseResult_NoResult(t *testing.T) {
+	messages := []string{
+		"",
+		"validation error: catalog `missing` not found",
+		"admission webhook \"apps.app-admission-controller.giantswarm.io\" denied the request: something went wrong",
+	}
+
+	for i, message := range messages {
+		t.Run(fmt.Sprintf("case %d", i), func(t *testing.T) {
+			result, ok := ParseResult(message)
+			if ok {
+				t.Fatalf("ParseResult(%#q) == %#v, want false", message, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package validation
+
+import (
+	"context"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/microerror"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+// Names of the built-in rules. They can be used in Config.EnabledRules and
+// Config.DisabledRules.
+const (
+	RuleCatalog             = "catalog"
+	RuleConfig              = "config"
+	RuleCordon              = "cordon"
+	RuleDependencies        = "dependencies"
+	RuleKubeConfig          = "kubeconfig"
+	RuleKubeVersion         = "kube-version"
+	RuleLabels              = "labels"
+	RuleMetadataConstraints = "metadata-constraints"
+	RuleName                = "name"
+	RuleNamespaceConfig     = "namespace-config"
+	RulePolicy              = "policy"
+	RuleQuota               = "quota"
+	RuleRelease             = "release"
+	RuleUserConfig          = "user-config"
+	// RuleUserConfigName enforces the user configmap and secret naming
+	// convention for apps in the default catalog.
+	RuleUserConfigName = "user-config-name"
+)
+
+// Rule is a single check run by ValidateApp.
+type Rule interface {
+	// Name returns the unique name of the rule.
+	Name() string
+	// AppliesTo returns whether the rule should be run for the app CR.
+	AppliesTo(cr v1alpha1.App) bool
+	// Validate returns an error if the app CR breaks the rule. Errors
+	// rejecting the app CR should be validation errors.
+	Validate(ctx context.Context, cr v1alpha1.App) error
+}
+
+// NewRule returns a rule built from the given functions. appliesTo may be
+// nil in which case the rule applies to all app CRs.
+func NewRule(name string, appliesTo func(cr v1alpha1.App) bool, validate func(ctx context.Context, cr v1alpha1.App) error) Rule {
+	if appliesTo == nil {
+		appliesTo = always
+	}
+
+	return &rule{
+		name:      name,
+		appliesTo: appliesTo,
+		validate:  validate,
+	}
+}
+
+type rule struct {
+	name      string
+	appliesTo func(cr v1alpha1.App) bool
+	validate  func(ctx context.Context, cr v1alpha1.App) error
+}
+
+func (r *rule) Name() string {
+	return r.name
+}
+
+func (r *rule) AppliesTo(cr v1alpha1.App) bool {
+	return r.appliesTo(cr)
+}
+
+func (r *rule) Validate(ctx context.Context, cr v1alpha1.App) error {
+	return r.validate(ctx, cr)
+}
+
+// builtinRules returns the built-in rules in the order they are run.
+func (v *Validator) builtinRules() []Rule {
+	return []Rule{
+		NewRule(RuleCatalog, hasCatalog, v.validateCatalog),
+		NewRule(RuleConfig, hasConfig, v.validateConfig),
+		NewRule(RuleCordon, nil, v.validateCordon),
+		NewRule(RuleDependencies, nil, v.validateDependencies),
+		NewRule(RuleKubeConfig, notInCluster, v.validateKubeConfig),
+		NewRule(RuleLabels, nil, v.validateLabels),
+		NewRule(RuleMetadataConstraints, nil, v.validateMetadataConstraints),
+		NewRule(RuleName, nil, v.validateName),
+		NewRule(RuleNamespaceConfig, nil, v.validateNamespaceConfig),
+		NewRule(RulePolicy, nil, v.validatePolicy),
+		NewRule(RuleQuota, nil, v.validateQuota),
+		NewRule(RuleRelease, nil, v.validateRelease),
+		NewRule(RuleUserConfigName, hasUserConfig, v.validateUserConfigName),
+		NewRule(RuleUserConfig, hasUserConfig, v.validateUserConfig),
+		// kube-version may query the target cluster so it runs last and
+		// app CRs rejected by the other rules do not wait for it.
+		NewRule(RuleKubeVersion, hasCatalog, v.validateKubeVersion),
+	}
+}
+
+// selectRules returns the built-in rules selected by the config followed by
+// the extra rules of the config.
+func selectRules(builtin []Rule, config Config) ([]Rule, error) {
+	names := map[string]bool{}
+	for _, r := range builtin {
+		names[r.Name()] = true
+	}
+
+	for _, name := range config.EnabledRules {
+		if !names[name] {
+			return nil, microerror.Maskf(invalidConfigError, "%T.EnabledRules contains unknown rule %#q", config, name)
+		}
+	}
+	for _, name := range config.DisabledRules {
+		if !names[name] {
+			return nil, microerror.Maskf(invalidConfigError, "%T.DisabledRules contains unknown rule %#q", config, name)
+		}
+	}
+
+	var rules []Rule
+	for _, r := range builtin {
+		if len(config.EnabledRules) > 0 && !containsString(config.EnabledRules, r.Name()) {
+			continue
+		}
+		if containsString(config.DisabledRules, r.Name()) {
+			continue
+		}
+
+		rules = append(rules, r)
+	}
+
+	for _, r := range config.Rules {
+		if r == nil {
+			return nil, microerror.Maskf(invalidConfigError, "%T.Rules must not contain nil rules", config)
+		}
+		if names[r.Name()] {
+			return nil, microerror.Maskf(invalidConfigError, "%T.Rules contains duplicate rule %#q", config, r.Name())
+		}
+		names[r.Name()] = true
+
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+func always(cr v1alpha1.App) bool {
+	return true
+}
+
+func hasCatalog(cr v1alpha1.App) bool {
+	return key.CatalogName(cr) != ""
+}
+
+func hasConfig(cr v1alpha1.App) bool {
+	return key.AppConfigMapName(cr) != "" || key.AppSecretName(cr) != ""
+}
+
+func hasUserConfig(cr v1alpha1.App) bool {
+	return key.UserConfigMapName(cr) != "" || key.UserSecretName(cr) != ""
+}
+
+func notInCluster(cr v1alpha1.App) bool {
+	return !key.InCluster(cr)
+}
+
+func containsString(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
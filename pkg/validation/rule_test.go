@@ -0,0 +1,134 @@
+package validation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/k8smetadata/pkg/label"
+	"github.com/giantswarm/microerror"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_Rules(t *testing.T) {
+	ctx := context.Background()
+
+	obj := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kiam",
+			Namespace: "eggs2",
+			Labels: map[string]string{
+				label.AppOperatorVersion: "0.0.0",
+			},
+		},
+		Spec: v1alpha1.AppSpec{
+			Catalog:   "default",
+			Name:      "kiam",
+			Namespace: "kube-system",
+			KubeConfig: v1alpha1.AppSpecKubeConfig{
+				InCluster: true,
+			},
+			UserConfig: v1alpha1.AppSpecUserConfig{
+				ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+					Name:      "kiam-values",
+					Namespace: "eggs2",
+				},
+			},
+			Version: "1.4.0",
+		},
+	}
+
+	denyKubeSystem := NewRule("deny-kube-system",
+		func(cr v1alpha1.App) bool {
+			return cr.Spec.Namespace == "kube-system"
+		},
+		func(ctx context.Context, cr v1alpha1.App) error {
+			return microerror.Maskf(validationError, "namespace %#q is reserved", cr.Spec.Namespace)
+		},
+	)
+
+	tests := []struct {
+		name         string
+		enabled      []string
+		disabled     []string
+		rules        []Rule
+		expectedErr  string
+		errorMatcher func(error) bool
+	}{
+		{
+			name:        "case 0: all built-in rules",
+			expectedErr: "validation error: user configmap must be named `kiam-user-values` for app in default catalog",
+		},
+		{
+			name:     "case 1: user configmap name rule disabled",
+			disabled: []string{RuleUserConfigName},
+		},
+		{
+			name:    "case 2: only selected rules",
+			enabled: []string{RuleCatalog, RuleLabels},
+		},
+		{
+			name:        "case 3: extra rule",
+			disabled:    []string{RuleUserConfigName},
+			rules:       []Rule{denyKubeSystem},
+			expectedErr: "validation error: namespace `kube-system` is reserved",
+		},
+		{
+			name:         "case 4: unknown rule",
+			disabled:     []string{"missing"},
+			errorMatcher: IsInvalidConfig,
+		},
+		{
+			name:         "case 5: duplicate rule",
+			rules:        []Rule{NewRule(RuleCatalog, nil, nil)},
+			errorMatcher: IsInvalidConfig,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(newTestCatalog("default", "default")),
+				K8sClient: clientgofake.NewSimpleClientset(newTestConfigMap("kiam-values", "eggs2")),
+				Logger:    microloggertest.New(),
+
+				EnabledRules:  tc.enabled,
+				DisabledRules: tc.disabled,
+				Rules:         tc.rules,
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			switch {
+			case err != nil && tc.errorMatcher == nil:
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.errorMatcher != nil:
+				t.Fatalf("error == nil, want non-nil")
+			case err != nil && !tc.errorMatcher(err):
+				t.Fatalf("error == %#v, want matching", err)
+			}
+
+			if tc.errorMatcher != nil {
+				return
+			}
+
+			_, err = r.ValidateApp(ctx, obj)
+			switch {
+			case err != nil && tc.expectedErr == "":
+				t.Fatalf("error == %#v, want nil", err)
+			case err == nil && tc.expectedErr != "":
+				t.Fatalf("error == nil, want non-nil")
+			}
+
+			if err != nil && tc.expectedErr != "" {
+				if !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("error == %#v, want %#v ", err.Error(), tc.expectedErr)
+				}
+			}
+		})
+	}
+}
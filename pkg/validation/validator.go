@@ -1,10 +1,15 @@
 package validation
 
 import (
+	"time"
+
 	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned"
 	"github.com/giantswarm/microerror"
 	"github.com/giantswarm/micrologger"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 )
 
 type Config struct {
@@ -12,6 +17,93 @@ type Config struct {
 	K8sClient kubernetes.Interface
 	Logger    micrologger.Logger
 
+	// AppCatalogEntryNamespace is optional. When set appcatalogentries are
+	// looked up in this namespace. By default they are looked up in the
+	// namespace of the catalog of the app.
+	AppCatalogEntryNamespace string
+	// AppQuota is optional. When set namespaces may hold at most this many
+	// app CRs. It can be overridden per namespace with the
+	// key.NamespaceAppQuotaAnnotation annotation, see NamespaceAppQuotas.
+	AppQuota int
+	// CatalogAppQuotas is optional. It limits the number of app CRs per
+	// catalog in a namespace. Limits can be overridden per namespace with the
+	// key.NamespaceCatalogAppQuotasAnnotation annotation, see
+	// NamespaceAppQuotas.
+	CatalogAppQuotas map[string]int
+	// CatalogNamespaces is optional. It is the order in which namespaces are
+	// searched for the catalog when the app CR does not specify
+	// .spec.catalogNamespace. Defaults to default and giantswarm.
+	CatalogNamespaces []string
+	// NamespaceAppQuotas is optional. When true the app quotas are read from
+	// the annotations of the namespace of the app CR. This requires
+	// permission to get namespaces unless NamespaceInformer is set.
+	NamespaceAppQuotas bool
+	// Concurrency is optional. ValidateAll validates up to this many app CRs
+	// at the same time. Defaults to 10.
+	Concurrency int
+	// CertificateExpiryWarningPeriod is optional. A warning is returned for
+	// kubeconfig certificates expiring within this period. Defaults to 7
+	// days.
+	CertificateExpiryWarningPeriod time.Duration
+	// KubeVersionGetter is optional. It is used to discover the Kubernetes
+	// version of the target cluster. Defaults to using the discovery API
+	// and reusing the version of each target cluster for a minute.
+	KubeVersionGetter KubeVersionGetter
+	// KubeVersionTimeout is optional. It limits the time the default
+	// KubeVersionGetter waits for remote clusters. The check is skipped
+	// when it expires. Defaults to 5 seconds.
+	KubeVersionTimeout time.Duration
+
+	// AppInformer, AppCatalogEntryInformer, CatalogInformer,
+	// ConfigMapInformer, NamespaceInformer and SecretInformer are optional.
+	// When set, resources are read from their caches once they are synced
+	// instead of from the Kubernetes API. The caller is responsible for running the informers.
+	// Indexes used by the validator are added to AppInformer so it must not
+	// be started before NewValidator is called. See NewAppInformer.
+	// Without a synced AppInformer the release and singleton rules list app
+	// CRs in all namespaces on every validation. Set it in large clusters.
+	// These lists are counted by the
+	// app_validation_cluster_wide_app_lists_total metric.
+	AppInformer             cache.SharedIndexInformer
+	AppCatalogEntryInformer cache.SharedIndexInformer
+	CatalogInformer         cache.SharedIndexInformer
+	ConfigMapInformer       cache.SharedIndexInformer
+	NamespaceInformer       cache.SharedIndexInformer
+	SecretInformer          cache.SharedIndexInformer
+
+	// Registerer is optional. When set the validation metrics are
+	// registered with it.
+	Registerer prometheus.Registerer
+
+	// Policy is optional. When set apps not allowed by the policy are
+	// rejected. See LoadPolicy.
+	Policy *Policy
+
+	// ReferencePolicy is optional. It controls which namespaces app CRs may
+	// reference configmaps and secrets in. Defaults to ReferencePolicyAny.
+	ReferencePolicy ReferencePolicy
+	// ReferenceAllowedNamespaces is optional. App CRs may reference
+	// configmaps and secrets in these namespaces with ReferencePolicyAllowList
+	// and ReferencePolicyOptIn.
+	ReferenceAllowedNamespaces []string
+
+	// DeniedNamespaceConfigPrefixes is optional. App CRs must not set
+	// namespace labels or annotations with keys starting with these
+	// prefixes. Prefixes ending with a slash also deny subdomains. Defaults
+	// to giantswarm.io/, k8s.io/, kubernetes.io/ and
+	// pod-security.kubernetes.io/ when nil. An empty slice denies no
+	// prefixes.
+	DeniedNamespaceConfigPrefixes []string
+
+	// EnabledRules is optional. When set only the built-in rules with these
+	// names are run. By default all built-in rules are run.
+	EnabledRules []string
+	// DisabledRules is optional. The built-in rules with these names are not
+	// run.
+	DisabledRules []string
+	// Rules is optional. These rules are run after the built-in rules.
+	Rules []Rule
+
 	Provider string
 }
 
@@ -20,10 +112,34 @@ type Validator struct {
 	k8sClient kubernetes.Interface
 	logger    micrologger.Logger
 
+	appInformer             cache.SharedIndexInformer
+	appCatalogEntryInformer cache.SharedIndexInformer
+	catalogInformer         cache.SharedIndexInformer
+	configMapInformer       cache.SharedIndexInformer
+	namespaceInformer       cache.SharedIndexInformer
+	secretInformer          cache.SharedIndexInformer
+
+	appCatalogEntryNamespace       string
+	appQuota                       int
+	catalogAppQuotas               map[string]int
+	catalogNamespaces              []string
+	concurrency                    int
+	certificateExpiryWarningPeriod time.Duration
+	deniedNamespaceConfigPrefixes  []string
+	kubeVersionGetter              KubeVersionGetter
+	metrics                        *metrics
+	namespaceAppQuotas             bool
+	policy                         *Policy
+	referenceAllowedNamespaces     []string
+	referencePolicy                ReferencePolicy
+	rules                          []Rule
+
 	provider string
 }
 
 func NewValidator(config Config) (*Validator, error) {
+	var err error
+
 	if config.G8sClient == nil {
 		return nil, microerror.Maskf(invalidConfigError, "%T.G8sClient must not be empty", config)
 	}
@@ -38,13 +154,108 @@ func NewValidator(config Config) (*Validator, error) {
 		return nil, microerror.Maskf(invalidConfigError, "%T.Provider must not be empty", config)
 	}
 
+	if config.Policy != nil {
+		err = config.Policy.Validate()
+		if err != nil {
+			return nil, microerror.Maskf(invalidConfigError, "%T.Policy is invalid: %s", config, err)
+		}
+	}
+
+	if config.AppQuota < 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.AppQuota must not be negative", config)
+	}
+	for catalog, limit := range config.CatalogAppQuotas {
+		if limit < 0 {
+			return nil, microerror.Maskf(invalidConfigError, "%T.CatalogAppQuotas must not be negative for catalog %#q", config, catalog)
+		}
+	}
+
+	if config.ReferencePolicy == "" {
+		config.ReferencePolicy = ReferencePolicyAny
+	}
+	if !containsReferencePolicy(referencePolicies, config.ReferencePolicy) {
+		return nil, microerror.Maskf(invalidConfigError, "%T.ReferencePolicy must be one of %v but is %#q", config, referencePolicies, config.ReferencePolicy)
+	}
+
+	if len(config.CatalogNamespaces) == 0 {
+		config.CatalogNamespaces = []string{metav1.NamespaceDefault, "giantswarm"}
+	}
+	if config.Concurrency == 0 {
+		config.Concurrency = defaultConcurrency
+	}
+	if config.Concurrency < 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.Concurrency must not be negative", config)
+	}
+	if config.KubeVersionTimeout < 0 {
+		return nil, microerror.Maskf(invalidConfigError, "%T.KubeVersionTimeout must not be negative", config)
+	}
+	if config.KubeVersionTimeout == 0 {
+		config.KubeVersionTimeout = defaultKubeVersionTimeout
+	}
+	if config.CertificateExpiryWarningPeriod == 0 {
+		config.CertificateExpiryWarningPeriod = defaultCertificateExpiryWarningPeriod
+	}
+	if config.DeniedNamespaceConfigPrefixes == nil {
+		config.DeniedNamespaceConfigPrefixes = defaultDeniedNamespaceConfigPrefixes
+	}
+
+	if config.AppInformer != nil {
+		err = addAppIndexers(config.AppInformer)
+		if err != nil {
+			return nil, microerror.Mask(err)
+		}
+	}
+
+	m, err := newMetrics(config.Registerer)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
 	validator := &Validator{
 		g8sClient: config.G8sClient,
 		k8sClient: config.K8sClient,
 		logger:    config.Logger,
 
+		appInformer:             config.AppInformer,
+		appCatalogEntryInformer: config.AppCatalogEntryInformer,
+		catalogInformer:         config.CatalogInformer,
+		configMapInformer:       config.ConfigMapInformer,
+		namespaceInformer:       config.NamespaceInformer,
+		secretInformer:          config.SecretInformer,
+
+		appCatalogEntryNamespace:       config.AppCatalogEntryNamespace,
+		appQuota:                       config.AppQuota,
+		catalogAppQuotas:               config.CatalogAppQuotas,
+		catalogNamespaces:              config.CatalogNamespaces,
+		concurrency:                    config.Concurrency,
+		certificateExpiryWarningPeriod: config.CertificateExpiryWarningPeriod,
+		deniedNamespaceConfigPrefixes:  config.DeniedNamespaceConfigPrefixes,
+		kubeVersionGetter:              config.KubeVersionGetter,
+		namespaceAppQuotas:             config.NamespaceAppQuotas,
+		metrics:                        m,
+		policy:                         config.Policy,
+		referenceAllowedNamespaces:     config.ReferenceAllowedNamespaces,
+		referencePolicy:                config.ReferencePolicy,
+
 		provider: config.Provider,
 	}
 
+	if config.KubeVersionGetter == nil {
+		validator.kubeVersionGetter = &kubeVersionGetter{
+			k8sClient: config.K8sClient,
+			metrics:   m,
+			timeout:   config.KubeVersionTimeout,
+
+			cacheTTL: kubeVersionCacheTTL,
+
+			getKubeConfigSecret: validator.getKubeConfigSecret,
+		}
+	}
+
+	validator.rules, err = selectRules(validator.builtinRules(), config)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
 	return validator, nil
 }
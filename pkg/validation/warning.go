@@ -0,0 +1,60 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/microerror"
+)
+
+type warningsKey struct{}
+
+// warnings collects the non-fatal findings of a single validation.
+type warnings struct {
+	mutex    sync.Mutex
+	messages []string
+}
+
+// AddWarning records a non-fatal finding for the app CR being validated.
+// Rules call it with the context passed to Rule.Validate. Warnings are
+// returned by ValidateAppWithWarnings and discarded by ValidateApp.
+func AddWarning(ctx context.Context, format string, args ...interface{}) {
+	w, ok := ctx.Value(warningsKey{}).(*warnings)
+	if !ok {
+		return
+	}
+
+	message := fmt.Sprintf(format, args...)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for _, m := range w.messages {
+		if m == message {
+			return
+		}
+	}
+
+	w.messages = append(w.messages, message)
+}
+
+// ValidateAppWithWarnings runs the same rules as ValidateApp. In addition
+// to the error rejecting the app CR it returns the non-fatal findings. They
+// can be set as warnings of the AdmissionReview response so they are shown
+// by kubectl.
+func (v *Validator) ValidateAppWithWarnings(ctx context.Context, app v1alpha1.App) ([]string, error) {
+	w := &warnings{}
+
+	_, err := v.ValidateApp(context.WithValue(ctx, warningsKey{}, w), app)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if err != nil {
+		return w.messages, microerror.Mask(err)
+	}
+
+	return w.messages, nil
+}
@@ -0,0 +1,143 @@
+package validation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/giantswarm/apiextensions/v3/pkg/apis/application/v1alpha1"
+	"github.com/giantswarm/apiextensions/v3/pkg/clientset/versioned/fake"
+	"github.com/giantswarm/k8smetadata/pkg/annotation"
+	"github.com/giantswarm/k8smetadata/pkg/label"
+	"github.com/giantswarm/micrologger/microloggertest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgofake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/giantswarm/app/v5/pkg/key"
+)
+
+func Test_ValidateAppWithWarnings(t *testing.T) {
+	ctx := context.Background()
+
+	cordonUntil := time.Now().UTC().Add(24 * time.Hour).Format(key.CordonUntilDateLayout)
+
+	tests := []struct {
+		name             string
+		annotations      map[string]string
+		entry            *v1alpha1.AppCatalogEntry
+		userConfigMapNS  string
+		expectedWarnings []string
+	}{
+		{
+			name: "case 0: no warnings",
+			entry: &v1alpha1.AppCatalogEntry{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "giantswarm-kiam-1.4.0",
+					Namespace: metav1.NamespaceDefault,
+				},
+			},
+			userConfigMapNS: "eggs2",
+		},
+		{
+			name:            "case 1: appcatalogentry not found",
+			userConfigMapNS: "eggs2",
+			expectedWarnings: []string{
+				"app `kiam` version `1.4.0` not found in catalog `giantswarm`, metadata restrictions are not validated",
+			},
+		},
+		{
+			name: "case 2: deprecated version",
+			entry: &v1alpha1.AppCatalogEntry{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "giantswarm-kiam-1.4.0",
+					Namespace: metav1.NamespaceDefault,
+					Annotations: map[string]string{
+						key.AppCatalogEntryDeprecatedAnnotation: "true",
+					},
+				},
+			},
+			userConfigMapNS: "eggs2",
+			expectedWarnings: []string{
+				"app `kiam` version `1.4.0` in catalog `giantswarm` is deprecated",
+			},
+		},
+		{
+			name: "case 3: cordoned app and user configmap in other namespace",
+			annotations: map[string]string{
+				annotation.AppOperatorCordonReason: "manual upgrade",
+				annotation.AppOperatorCordonUntil:  cordonUntil,
+			},
+			entry: &v1alpha1.AppCatalogEntry{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "giantswarm-kiam-1.4.0",
+					Namespace: metav1.NamespaceDefault,
+				},
+			},
+			userConfigMapNS: "giantswarm",
+			expectedWarnings: []string{
+				"app `kiam` is cordoned until `" + cordonUntil + "` with reason `manual upgrade` and will not be updated",
+				"user configmap `kiam-user-values` is in namespace `giantswarm` and not in app namespace `eggs2`",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "kiam",
+					Namespace:   "eggs2",
+					Annotations: tc.annotations,
+					Labels: map[string]string{
+						label.AppOperatorVersion: "0.0.0",
+					},
+				},
+				Spec: v1alpha1.AppSpec{
+					Catalog:   "giantswarm",
+					Name:      "kiam",
+					Namespace: "kube-system",
+					KubeConfig: v1alpha1.AppSpecKubeConfig{
+						InCluster: true,
+					},
+					UserConfig: v1alpha1.AppSpecUserConfig{
+						ConfigMap: v1alpha1.AppSpecUserConfigConfigMap{
+							Name:      "kiam-user-values",
+							Namespace: tc.userConfigMapNS,
+						},
+					},
+					Version: "1.4.0",
+				},
+			}
+
+			g8sObjs := []runtime.Object{
+				newTestCatalog("giantswarm", "default"),
+			}
+			if tc.entry != nil {
+				g8sObjs = append(g8sObjs, tc.entry)
+			}
+
+			c := Config{
+				G8sClient: fake.NewSimpleClientset(g8sObjs...),
+				K8sClient: clientgofake.NewSimpleClientset(newTestConfigMap("kiam-user-values", tc.userConfigMapNS)),
+				Logger:    microloggertest.New(),
+
+				Provider: "aws",
+			}
+			r, err := NewValidator(c)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			warnings, err := r.ValidateAppWithWarnings(ctx, obj)
+			if err != nil {
+				t.Fatalf("error == %#v, want nil", err)
+			}
+
+			if !reflect.DeepEqual(warnings, tc.expectedWarnings) {
+				t.Fatalf("warnings == %#v, want %#v", warnings, tc.expectedWarnings)
+			}
+		})
+	}
+}
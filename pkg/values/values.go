@@ -64,6 +64,30 @@ func (v *Values) MergeAll(ctx context.Context, app v1alpha1.App, catalog v1alpha
 	return configMapData, nil
 }
 
+// ExtractConfigMapData parses configmap data the same way MergeConfigMapData
+// does. The data must have a single key holding YAML values. A parsingError
+// is returned otherwise. name is used in error messages, e.g. user.
+func ExtractConfigMapData(name string, data map[string]string) (map[string]interface{}, error) {
+	rawMapData, err := extractData(configmap, name, data)
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return rawMapData, nil
+}
+
+// ExtractSecretData parses secret data the same way MergeSecretData does.
+// The data must have a single key holding YAML values. A parsingError is
+// returned otherwise. name is used in error messages, e.g. user.
+func ExtractSecretData(name string, data map[string][]byte) (map[string]interface{}, error) {
+	rawMapData, err := extractData(secret, name, toStringMap(data))
+	if err != nil {
+		return nil, microerror.Mask(err)
+	}
+
+	return rawMapData, nil
+}
+
 func extractData(resourceType, name string, data map[string]string) (map[string]interface{}, error) {
 	var err error
 	var rawMapData map[string]interface{}